@@ -0,0 +1,242 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+)
+
+// DefaultBaseDerivationPath is the BIP-44 path of the accounts of an HD wallet, the account
+// of index i is derived at DefaultBaseDerivationPath/i like in MetaMask.
+const DefaultBaseDerivationPath = "m/44'/60'/0'/0"
+
+// hardenedKeyStart is the index of the first hardened child key.
+const hardenedKeyStart = 0x80000000
+
+// NewMnemonic generates a BIP-39 mnemonic from bits of entropy, a multiple of 32 between 128
+// and 256, 128 giving 12 words and 256 giving 24 words.
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+type hdWalletOption struct {
+	basePath string
+}
+
+type HDWalletOptionFunc func(*hdWalletOption)
+
+// WithBaseDerivationPath sets the path the accounts are derived under instead of
+// DefaultBaseDerivationPath.
+func WithBaseDerivationPath(path string) HDWalletOptionFunc {
+	return func(o *hdWalletOption) {
+		o.basePath = path
+	}
+}
+
+// HDWallet derives the L1 keys of many accounts from a single BIP-39 mnemonic along BIP-44
+// paths, and the L2 key of each account from its L1 key with GenerateSeed. Its methods are
+// safe for concurrent use.
+type HDWallet struct {
+	chainId  uint64
+	basePath gethaccounts.DerivationPath
+	master   *extendedKey
+
+	mu       sync.Mutex
+	accounts map[uint32]*HDAccount
+}
+
+// HDAccount is an account of an HD wallet.
+type HDAccount struct {
+	Index uint32
+	// Path is the derivation path of the L1 key
+	Path    string
+	Address string
+	// ChainId is the chain id the L2 key is derived for
+	ChainId    uint64
+	privateKey *ecdsa.PrivateKey
+	seed       string
+	keyManager KeyManager
+	l1Signer   signer.L1Signer
+}
+
+// NewHDWallet creates a wallet from a BIP-39 mnemonic and its optional passphrase, the L2 keys
+// are derived for the given chain id.
+func NewHDWallet(mnemonic, passphrase string, chainId uint64, options ...HDWalletOptionFunc) (*HDWallet, error) {
+	opt := &hdWalletOption{basePath: DefaultBaseDerivationPath}
+	for _, o := range options {
+		o(opt)
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	basePath, err := gethaccounts.ParseDerivationPath(opt.basePath)
+	if err != nil {
+		return nil, err
+	}
+	master, err := newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &HDWallet{
+		chainId:  chainId,
+		basePath: basePath,
+		master:   master,
+		accounts: make(map[uint32]*HDAccount),
+	}, nil
+}
+
+// Account returns the account of the given index, its keys are derived on the first call.
+func (w *HDWallet) Account(index uint32) (*HDAccount, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if account, ok := w.accounts[index]; ok {
+		return account, nil
+	}
+	if index >= hardenedKeyStart {
+		return nil, fmt.Errorf("invalid account index %d", index)
+	}
+
+	path := append(append(gethaccounts.DerivationPath{}, w.basePath...), index)
+	key := w.master
+	for _, i := range path {
+		var err error
+		if key, err = key.child(i); err != nil {
+			return nil, fmt.Errorf("derive %s: %v", path, err)
+		}
+	}
+	privateKey, err := crypto.ToECDSA(key.key)
+	if err != nil {
+		return nil, err
+	}
+	hexKey := common.Bytes2Hex(crypto.FromECDSA(privateKey))
+	l1Signer, err := signer.NewL1Singer(hexKey)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := GenerateSeed(hexKey, w.chainId)
+	if err != nil {
+		return nil, err
+	}
+	keyManager, err := NewSeedKeyManager(seed)
+	if err != nil {
+		return nil, err
+	}
+	account := &HDAccount{
+		Index:      index,
+		Path:       path.String(),
+		Address:    l1Signer.GetAddress(),
+		ChainId:    w.chainId,
+		privateKey: privateKey,
+		seed:       seed,
+		keyManager: keyManager,
+		l1Signer:   l1Signer,
+	}
+	w.accounts[index] = account
+	return account, nil
+}
+
+// Accounts returns count accounts starting at index from.
+func (w *HDWallet) Accounts(from, count uint32) ([]*HDAccount, error) {
+	accounts := make([]*HDAccount, 0, count)
+	for i := uint32(0); i < count; i++ {
+		account, err := w.Account(from + i)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// ChainId returns the chain id the L2 keys are derived for.
+func (w *HDWallet) ChainId() uint64 {
+	return w.chainId
+}
+
+// PrivateKey returns the hex encoded L1 private key.
+func (a *HDAccount) PrivateKey() string {
+	return common.Bytes2Hex(crypto.FromECDSA(a.privateKey))
+}
+
+// Seed returns the seed of the L2 key, as returned by GenerateSeed.
+func (a *HDAccount) Seed() string {
+	return a.seed
+}
+
+func (a *HDAccount) KeyManager() KeyManager {
+	return a.keyManager
+}
+
+func (a *HDAccount) L1Signer() signer.L1Signer {
+	return a.l1Signer
+}
+
+// extendedKey is a BIP-32 extended secp256k1 private key.
+type extendedKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+var errInvalidChildKey = errors.New("invalid child key")
+
+func newMasterKey(seed []byte) (*extendedKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	if !validKey(new(big.Int).SetBytes(sum[:32])) {
+		return nil, errInvalidChildKey
+	}
+	return &extendedKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// child derives the child key of index i, hardened when i >= 2^31.
+func (k *extendedKey) child(i uint32) (*extendedKey, error) {
+	var data []byte
+	if i >= hardenedKeyStart {
+		data = append([]byte{0}, k.key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], i)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, errInvalidChildKey
+	}
+	childKey := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	childKey.Mod(childKey, n)
+	if !validKey(childKey) {
+		return nil, errInvalidChildKey
+	}
+	return &extendedKey{key: common.LeftPadBytes(childKey.Bytes(), 32), chainCode: sum[32:]}, nil
+}
+
+func validKey(key *big.Int) bool {
+	return key.Sign() > 0 && key.Cmp(crypto.S256().Params().N) < 0
+}
@@ -0,0 +1,68 @@
+package accounts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWallet(t *testing.T) {
+	wallet, err := NewHDWallet(testMnemonic, "", 97)
+	assert.NoError(t, err)
+
+	// the addresses of the well known test mnemonic
+	accounts, err := wallet.Accounts(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", accounts[0].Address)
+	assert.Equal(t, "m/44'/60'/0'/0/0", accounts[0].Path)
+	assert.Equal(t, "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", accounts[0].PrivateKey())
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", accounts[1].Address)
+	assert.Equal(t, uint32(1), accounts[1].Index)
+
+	// the L2 key is the one derived from the L1 key
+	seed, err := GenerateSeed(accounts[1].PrivateKey(), 97)
+	assert.NoError(t, err)
+	assert.Equal(t, seed, accounts[1].Seed())
+	keyManager, _ := NewSeedKeyManager(seed)
+	assert.Equal(t, keyManager.PubKey().Bytes(), accounts[1].KeyManager().PubKey().Bytes())
+	assert.Equal(t, accounts[1].Address, accounts[1].L1Signer().GetAddress())
+	account, err := wallet.Account(1)
+	assert.NoError(t, err)
+	assert.Same(t, accounts[1], account)
+
+	// the passphrase and the path change the keys
+	withPassphrase, err := NewHDWallet(testMnemonic, "passphrase", 97)
+	assert.NoError(t, err)
+	account, err = withPassphrase.Account(0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, accounts[0].Address, account.Address)
+	ledgerLive, err := NewHDWallet(testMnemonic, "", 97, WithBaseDerivationPath("m/44'/60'/1'/0"))
+	assert.NoError(t, err)
+	account, err = ledgerLive.Account(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "m/44'/60'/1'/0/0", account.Path)
+	assert.NotEqual(t, accounts[0].Address, account.Address)
+
+	_, err = wallet.Account(hardenedKeyStart)
+	assert.Error(t, err)
+	_, err = NewHDWallet("test test test", "", 97)
+	assert.Error(t, err)
+	_, err = NewHDWallet(testMnemonic, "", 97, WithBaseDerivationPath("m/x"))
+	assert.Error(t, err)
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(128)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 12)
+	_, err = NewHDWallet(mnemonic, "", 97)
+	assert.NoError(t, err)
+	mnemonic, err = NewMnemonic(256)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+	_, err = NewMnemonic(100)
+	assert.Error(t, err)
+}
@@ -1,11 +1,22 @@
 package accounts
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"hash"
+	"os"
+	"reflect"
+	"strings"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
 	"github.com/consensys/gnark-crypto/signature"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/bnb-chain/zkbnb-crypto/ecc/ztwistededwards/tebn254"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
 )
 
 type Signer interface {
@@ -18,6 +29,8 @@ type KeyManager interface {
 	PubKeyPoint() [2][32]byte
 }
 
+// seedKeyManager signs with a tebn254 private key held in memory, derived from a seed or
+// given as is.
 type seedKeyManager struct {
 	key *tebn254.PrivateKey
 }
@@ -30,6 +43,50 @@ func NewSeedKeyManager(seed string) (KeyManager, error) {
 	return &seedKeyManager{key: key}, nil
 }
 
+// NewPrivateKeyManager creates a key manager from the hex encoded bytes of a tebn254 private
+// key, as returned by PrivateKeyFromSeed.
+func NewPrivateKeyManager(privateKey string) (KeyManager, error) {
+	buf, err := hexutil.Decode(ensureHexPrefix(strings.TrimSpace(privateKey)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	key := &tebn254.PrivateKey{}
+	if n, err := key.SetBytes(buf); err != nil || n != len(buf) {
+		return nil, errors.New("invalid private key")
+	}
+	return &seedKeyManager{key: key}, nil
+}
+
+// PrivateKeyFromSeed returns the hex encoded bytes of the tebn254 private key derived from
+// the seed, to be used with NewPrivateKeyManager or SaveKeyFile.
+func PrivateKeyFromSeed(seed string) (string, error) {
+	key, err := tebn254.GenerateEddsaPrivateKey(seed)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key.Bytes()), nil
+}
+
+// SaveKeyFile writes the hex encoded tebn254 private key to a file readable by its owner
+// only. An existing file is never overwritten. The key is not encrypted, see SaveSeedKeystore
+// for an encrypted alternative.
+func SaveKeyFile(path, privateKey string) error {
+	if _, err := NewPrivateKeyManager(privateKey); err != nil {
+		return err
+	}
+	return writeKeystore(path, []byte(ensureHexPrefix(privateKey)+"\n"))
+}
+
+// NewKeyManagerFromFile creates a key manager from a file holding a hex encoded tebn254
+// private key, as written by SaveKeyFile.
+func NewKeyManagerFromFile(path string) (KeyManager, error) {
+	privateKey, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyManager(string(privateKey))
+}
+
 func (key *seedKeyManager) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
 	return key.key.Sign(message, hFunc)
 }
@@ -39,7 +96,89 @@ func (key *seedKeyManager) PubKey() signature.PublicKey {
 }
 
 func (key *seedKeyManager) PubKeyPoint() (res [2][32]byte) {
-	copy(res[0][:], key.key.PublicKey.A.X.Marshal())
-	copy(res[1][:], key.key.PublicKey.A.Y.Marshal())
+	return pubKeyPoint(&key.key.PublicKey)
+}
+
+func pubKeyPoint(pubKey *eddsa.PublicKey) (res [2][32]byte) {
+	copy(res[0][:], pubKey.A.X.Marshal())
+	copy(res[1][:], pubKey.A.Y.Marshal())
 	return res
 }
+
+func ensureHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s
+	}
+	return "0x" + s
+}
+
+const (
+	// RemoteEdDSAPubKeyMethod is the JSON-RPC method called by a remote key manager to get its
+	// public key. Its param is the key id and its result is the hex encoded compressed
+	// tebn254 public key.
+	RemoteEdDSAPubKeyMethod = "zkbnb_eddsaPubKey"
+	// RemoteEdDSASignMethod is the JSON-RPC method called by a remote key manager to sign. Its
+	// params are the key id and the hex encoded message, the MiMC hash of an l2 tx, and its
+	// result is the hex encoded EdDSA signature of the message, hashed with MiMC.
+	RemoteEdDSASignMethod = "zkbnb_eddsaSign"
+)
+
+// mimcType is the type of the hash functions created by mimc.NewMiMC, the only ones the
+// remote signing services hash with.
+var mimcType = reflect.TypeOf(mimc.NewMiMC())
+
+// remoteKeyManager delegates the signatures to an external signing service, so that the
+// tebn254 key never enters the process.
+type remoteKeyManager struct {
+	client *signer.RemoteClient
+	keyId  string
+	pubKey *eddsa.PublicKey
+}
+
+// NewRemoteKeyManager creates a key manager for the key keyId of the signing service at the
+// JSON-RPC endpoint url, see RemoteEdDSAPubKeyMethod and RemoteEdDSASignMethod for the protocol. The
+// public key is fetched once and every signature is verified against it. Only MiMC is
+// supported as hash function, as used by every l2 tx, Sign fails with any other.
+func NewRemoteKeyManager(url, keyId string, options ...signer.RemoteSignerOptionFunc) (KeyManager, error) {
+	client := signer.NewRemoteClient(url, options...)
+	var pubKeyHex string
+	if err := client.Call(context.Background(), RemoteEdDSAPubKeyMethod, &pubKeyHex, keyId); err != nil {
+		return nil, err
+	}
+	buf, err := hexutil.Decode(ensureHexPrefix(pubKeyHex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %v", pubKeyHex, err)
+	}
+	pubKey := &eddsa.PublicKey{}
+	if _, err := pubKey.SetBytes(buf); err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %v", pubKeyHex, err)
+	}
+	return &remoteKeyManager{client: client, keyId: keyId, pubKey: pubKey}, nil
+}
+
+func (key *remoteKeyManager) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if reflect.TypeOf(hFunc) != mimcType {
+		return nil, fmt.Errorf("the remote signer only hashes with MiMC, not %T", hFunc)
+	}
+	var sigHex string
+	if err := key.client.Call(context.Background(), RemoteEdDSASignMethod, &sigHex, key.keyId, hexutil.Encode(message)); err != nil {
+		return nil, err
+	}
+	sig, err := hexutil.Decode(ensureHexPrefix(sigHex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %s from the remote signer", sigHex)
+	}
+	valid, err := key.pubKey.Verify(sig, message, mimc.NewMiMC())
+	if err != nil || !valid {
+		return nil, fmt.Errorf("invalid signature %s from the remote signer", sigHex)
+	}
+	return sig, nil
+}
+
+func (key *remoteKeyManager) PubKey() signature.PublicKey {
+	return key.pubKey
+}
+
+func (key *remoteKeyManager) PubKeyPoint() [2][32]byte {
+	return pubKeyPoint(key.pubKey)
+}
@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateKeyManager(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	seed, err := GenerateSeed(common.Bytes2Hex(crypto.FromECDSA(key)), 97)
+	assert.NoError(t, err)
+	seedKeyManager, err := NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	privateKey, err := PrivateKeyFromSeed(seed)
+	assert.NoError(t, err)
+
+	keyManager, err := NewPrivateKeyManager(privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, seedKeyManager.PubKey().Bytes(), keyManager.PubKey().Bytes())
+	assert.Equal(t, seedKeyManager.PubKeyPoint(), keyManager.PubKeyPoint())
+	// messages are MiMC hashes, a field element
+	message := common.LeftPadBytes([]byte("zkbnb"), 32)
+	signature, err := keyManager.Sign(message, mimc.NewMiMC())
+	assert.NoError(t, err)
+	valid, err := seedKeyManager.PubKey().Verify(signature, message, mimc.NewMiMC())
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// the 0x prefix is optional
+	keyManager, err = NewPrivateKeyManager(privateKey[2:])
+	assert.NoError(t, err)
+	assert.Equal(t, seedKeyManager.PubKeyPoint(), keyManager.PubKeyPoint())
+	_, err = NewPrivateKeyManager("0x1234")
+	assert.Error(t, err)
+	_, err = NewPrivateKeyManager("zkbnb")
+	assert.Error(t, err)
+}
+
+func TestKeyFile(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	seed, err := GenerateSeed(common.Bytes2Hex(crypto.FromECDSA(key)), 97)
+	assert.NoError(t, err)
+	privateKey, err := PrivateKeyFromSeed(seed)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "keys", "l2.key")
+
+	assert.NoError(t, SaveKeyFile(path, privateKey))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	// existing key files are not overwritten
+	assert.Error(t, SaveKeyFile(path, privateKey))
+	assert.Error(t, SaveKeyFile(filepath.Join(t.TempDir(), "invalid.key"), "0x1234"))
+
+	keyManager, err := NewKeyManagerFromFile(path)
+	assert.NoError(t, err)
+	seedKeyManager, _ := NewSeedKeyManager(seed)
+	assert.Equal(t, seedKeyManager.PubKeyPoint(), keyManager.PubKeyPoint())
+	_, err = NewKeyManagerFromFile(filepath.Join(t.TempDir(), "missing.key"))
+	assert.Error(t, err)
+}
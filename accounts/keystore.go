@@ -0,0 +1,203 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+)
+
+const (
+	// SeedKeystoreVersion is the version of the seed keystores written by SaveSeedKeystore.
+	SeedKeystoreVersion = 1
+	// SeedKeystoreType identifies the seed keystores, to tell them apart from L1 keystores.
+	SeedKeystoreType = "tebn254-seed"
+)
+
+type keystoreOption struct {
+	scryptN int
+	scryptP int
+}
+
+type KeystoreOptionFunc func(*keystoreOption)
+
+// WithScrypt sets the scrypt parameters used to derive the encryption key from the
+// passphrase. The default is keystore.StandardScryptN and keystore.StandardScryptP,
+// keystore.LightScryptN and keystore.LightScryptP are faster but weaker.
+func WithScrypt(n, p int) KeystoreOptionFunc {
+	return func(o *keystoreOption) {
+		o.scryptN = n
+		o.scryptP = p
+	}
+}
+
+func newKeystoreOption(options []KeystoreOptionFunc) *keystoreOption {
+	opt := &keystoreOption{
+		scryptN: keystore.StandardScryptN,
+		scryptP: keystore.StandardScryptP,
+	}
+	for _, o := range options {
+		o(opt)
+	}
+	return opt
+}
+
+// SaveL1Keystore encrypts the hex encoded L1 private key into an Ethereum V3 keystore file,
+// as written by geth or MetaMask. An existing file is never overwritten.
+func SaveL1Keystore(path, privateKey, passphrase string, options ...KeystoreOptionFunc) error {
+	opt := newKeystoreOption(options)
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	keyJson, err := keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, passphrase, opt.scryptN, opt.scryptP)
+	if err != nil {
+		return err
+	}
+	return writeKeystore(path, keyJson)
+}
+
+// LoadL1Keystore decrypts the L1 private key of an Ethereum V3 keystore file.
+func LoadL1Keystore(path, passphrase string) (*ecdsa.PrivateKey, error) {
+	keyJson, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJson, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt l1 keystore %s: %w", path, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// NewL1SignerFromKeystore creates an L1 signer with the key of an Ethereum V3 keystore file.
+func NewL1SignerFromKeystore(path, passphrase string) (signer.L1Signer, error) {
+	key, err := LoadL1Keystore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewL1Singer(hex.EncodeToString(crypto.FromECDSA(key)))
+}
+
+// seedKeystore is the encrypted form of a seed. The seed is encrypted like the key of an
+// Ethereum V3 keystore, with scrypt, aes-128-ctr and a keccak256 mac.
+type seedKeystore struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"`
+	Id      string `json:"id"`
+	// Address is the L1 address of the account, if known
+	Address string `json:"address,omitempty"`
+	// PubKey is the hex encoded compressed EdDSA public key derived from the seed
+	PubKey string              `json:"pubkey"`
+	Crypto keystore.CryptoJSON `json:"crypto"`
+}
+
+// SaveSeedKeystore encrypts the seed of an EdDSA key, e.g. one from GenerateSeed, into a
+// keystore file. The L1 address of the account can be stored along, it may be empty. An
+// existing file is never overwritten.
+func SaveSeedKeystore(path, seed, address, passphrase string, options ...KeystoreOptionFunc) error {
+	opt := newKeystoreOption(options)
+	if address != "" && !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid l1 address %s", address)
+	}
+	keyManager, err := NewSeedKeyManager(seed)
+	if err != nil {
+		return err
+	}
+	cryptoJson, err := keystore.EncryptDataV3([]byte(seed), []byte(passphrase), opt.scryptN, opt.scryptP)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	keyJson, err := json.Marshal(&seedKeystore{
+		Version: SeedKeystoreVersion,
+		Type:    SeedKeystoreType,
+		Id:      id.String(),
+		Address: address,
+		PubKey:  common.Bytes2Hex(keyManager.PubKey().Bytes()),
+		Crypto:  cryptoJson,
+	})
+	if err != nil {
+		return err
+	}
+	return writeKeystore(path, keyJson)
+}
+
+// LoadSeedKeystore decrypts the seed of a keystore file written by SaveSeedKeystore and
+// returns it with the L1 address stored along.
+func LoadSeedKeystore(path, passphrase string) (seed, address string, err error) {
+	keyJson, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	ks := &seedKeystore{}
+	if err := json.Unmarshal(keyJson, ks); err != nil {
+		return "", "", fmt.Errorf("invalid seed keystore %s: %v", path, err)
+	}
+	if ks.Type != SeedKeystoreType {
+		return "", "", fmt.Errorf("%s is not a seed keystore", path)
+	}
+	if ks.Version != SeedKeystoreVersion {
+		return "", "", fmt.Errorf("unsupported seed keystore version %d", ks.Version)
+	}
+	plain, err := keystore.DecryptDataV3(ks.Crypto, passphrase)
+	if err != nil {
+		return "", "", fmt.Errorf("decrypt seed keystore %s: %w", path, err)
+	}
+	keyManager, err := NewSeedKeyManager(string(plain))
+	if err != nil {
+		return "", "", err
+	}
+	if pubKey := common.Bytes2Hex(keyManager.PubKey().Bytes()); pubKey != ks.PubKey {
+		return "", "", errors.New("the seed does not match the public key of the keystore")
+	}
+	return string(plain), ks.Address, nil
+}
+
+// NewSeedKeyManagerFromKeystore creates a key manager with the seed of a keystore file
+// written by SaveSeedKeystore.
+func NewSeedKeyManagerFromKeystore(path, passphrase string) (KeyManager, error) {
+	seed, _, err := LoadSeedKeystore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeedKeyManager(seed)
+}
+
+func writeKeystore(path string, keyJson []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(keyJson); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
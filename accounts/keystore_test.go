@@ -0,0 +1,71 @@
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestL1Keystore(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	path := filepath.Join(t.TempDir(), "keystore", "l1.json")
+
+	assert.NoError(t, SaveL1Keystore(path, privateKey, "passphrase", WithScrypt(keystore.LightScryptN, keystore.LightScryptP)))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	// existing keystores are not overwritten
+	assert.Error(t, SaveL1Keystore(path, privateKey, "passphrase", WithScrypt(keystore.LightScryptN, keystore.LightScryptP)))
+
+	loaded, err := LoadL1Keystore(path, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, key.D, loaded.D)
+	l1Signer, err := NewL1SignerFromKeystore(path, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), l1Signer.GetAddress())
+	_, err = LoadL1Keystore(path, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	// a keystore written by geth is read as well
+	keyJson, err := keystore.EncryptKey(&keystore.Key{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}, "geth", keystore.LightScryptN, keystore.LightScryptP)
+	assert.NoError(t, err)
+	gethPath := filepath.Join(t.TempDir(), "geth.json")
+	assert.NoError(t, os.WriteFile(gethPath, keyJson, 0600))
+	loaded, err = LoadL1Keystore(gethPath, "geth")
+	assert.NoError(t, err)
+	assert.Equal(t, key.D, loaded.D)
+}
+
+func TestSeedKeystore(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	seed, err := GenerateSeed(common.Bytes2Hex(crypto.FromECDSA(key)), 97)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "seed.json")
+
+	assert.NoError(t, SaveSeedKeystore(path, seed, address, "passphrase", WithScrypt(keystore.LightScryptN, keystore.LightScryptP)))
+	loaded, loadedAddress, err := LoadSeedKeystore(path, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, seed, loaded)
+	assert.Equal(t, address, loadedAddress)
+	keyManager, err := NewSeedKeyManagerFromKeystore(path, "passphrase")
+	assert.NoError(t, err)
+	expected, _ := NewSeedKeyManager(seed)
+	assert.Equal(t, expected.PubKey().Bytes(), keyManager.PubKey().Bytes())
+
+	_, _, err = LoadSeedKeystore(path, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+	assert.Error(t, SaveSeedKeystore(filepath.Join(t.TempDir(), "bad.json"), seed, "dead", "passphrase"))
+
+	// an L1 keystore is not taken for a seed keystore
+	l1Path := filepath.Join(t.TempDir(), "l1.json")
+	assert.NoError(t, SaveL1Keystore(l1Path, common.Bytes2Hex(crypto.FromECDSA(key)), "passphrase", WithScrypt(keystore.LightScryptN, keystore.LightScryptP)))
+	_, _, err = LoadSeedKeystore(l1Path, "passphrase")
+	assert.Error(t, err)
+}
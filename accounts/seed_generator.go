@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bnb-chain/zkbnb-go-sdk/signer"
 	"strings"
@@ -8,20 +9,38 @@ import (
 
 const SEED_FORMAT = "Access zkbnb account.\n\nOnly sign this message for a trusted client!\nChain ID: %d."
 
+// ErrNondeterministicSeed is returned by GenerateSeedWithSigner when the L1 signer gives
+// different signatures of the seed message, so that no stable L2 key can be derived from it.
+var ErrNondeterministicSeed = errors.New("the l1 signer does not sign the seed message deterministically")
+
 func GenerateSeed(privateKey string, chainId uint64) (string, error) {
-	signer, err := signer.NewL1Singer(privateKey)
+	l1Signer, err := signer.NewL1Singer(privateKey)
 	if err != nil {
 		return "", err
 	}
+	return GenerateSeedWithSigner(l1Signer, chainId)
+}
 
+// GenerateSeedWithSigner generates the seed with any L1 signer, e.g. a remote signer. The
+// seed is the signature of the seed message, which is signed twice: signers whose signatures
+// are not reproducible, as some KMS and HSM backed ones, fail with ErrNondeterministicSeed
+// and their L2 key has to be kept apart, e.g. in a seed keystore.
+func GenerateSeedWithSigner(l1Signer signer.L1Signer, chainId uint64) (string, error) {
 	messageText := fmt.Sprintf(SEED_FORMAT, chainId)
-	seedString, err := signer.Sign(messageText)
-	// if seedString starts with 0x as the prefix, directly trim the 0x prefix
-	if strings.HasPrefix(seedString, "0x") {
-		seedString = seedString[2:]
+	seedString, err := l1Signer.Sign(messageText)
+	if err != nil {
+		return "", err
 	}
+	again, err := l1Signer.Sign(messageText)
 	if err != nil {
 		return "", err
 	}
+	if !strings.EqualFold(seedString, again) {
+		return "", ErrNondeterministicSeed
+	}
+	// if seedString starts with 0x as the prefix, directly trim the 0x prefix
+	if strings.HasPrefix(seedString, "0x") {
+		seedString = seedString[2:]
+	}
 	return seedString, nil
 }
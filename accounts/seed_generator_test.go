@@ -0,0 +1,41 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+)
+
+// randomizedSigner signs with a fresh key every time, like a signer whose signatures are
+// not reproducible.
+type randomizedSigner struct {
+	signer.L1Signer
+}
+
+func (s randomizedSigner) Sign(body string) (string, error) {
+	key, _ := crypto.GenerateKey()
+	l1Signer, err := signer.NewL1Singer(common.Bytes2Hex(crypto.FromECDSA(key)))
+	if err != nil {
+		return "", err
+	}
+	return l1Signer.Sign(body)
+}
+
+func TestGenerateSeedWithSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	seed, err := GenerateSeedWithSigner(l1Signer, 97)
+	assert.NoError(t, err)
+	expected, err := GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, seed)
+
+	_, err = GenerateSeedWithSigner(randomizedSigner{l1Signer}, 97)
+	assert.ErrorIs(t, err, ErrNondeterministicSeed)
+}
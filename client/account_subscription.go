@@ -0,0 +1,320 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// AccountEventType is the kind of activity of an account, seen from the account.
+type AccountEventType int
+
+const (
+	AccountEventTransferIn AccountEventType = iota + 1
+	AccountEventTransferOut
+	AccountEventDeposit
+	AccountEventDepositNft
+	AccountEventWithdraw
+	AccountEventWithdrawNft
+	AccountEventFullExit
+	AccountEventFullExitNft
+	AccountEventChangePubKey
+	AccountEventCreateCollection
+	// AccountEventMintNft is emitted to the creator and to the receiver of the minted nft
+	AccountEventMintNft
+	AccountEventTransferNftIn
+	AccountEventTransferNftOut
+	AccountEventCancelOffer
+	// AccountEventAtomicMatch is emitted to the buyer and the seller, and to the submitter
+	AccountEventAtomicMatch
+)
+
+var accountEventNames = map[AccountEventType]string{
+	AccountEventTransferIn:       "TransferIn",
+	AccountEventTransferOut:      "TransferOut",
+	AccountEventDeposit:          "Deposit",
+	AccountEventDepositNft:       "DepositNft",
+	AccountEventWithdraw:         "Withdraw",
+	AccountEventWithdrawNft:      "WithdrawNft",
+	AccountEventFullExit:         "FullExit",
+	AccountEventFullExitNft:      "FullExitNft",
+	AccountEventChangePubKey:     "ChangePubKey",
+	AccountEventCreateCollection: "CreateCollection",
+	AccountEventMintNft:          "MintNft",
+	AccountEventTransferNftIn:    "TransferNftIn",
+	AccountEventTransferNftOut:   "TransferNftOut",
+	AccountEventCancelOffer:      "CancelOffer",
+	AccountEventAtomicMatch:      "AtomicMatch",
+}
+
+func (t AccountEventType) String() string {
+	if name, ok := accountEventNames[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// AccountEvent is a tx of the subscribed account which reached a new status.
+type AccountEvent struct {
+	Type AccountEventType
+	// Status is the status reached by the tx, one of the types.TxStatus constants
+	Status int64
+	Tx     *types.Tx
+	// Counterparty is the L1 address of the other side of a transfer or an nft mint, empty
+	// for the other txs
+	Counterparty string
+	// AssetId and Amount are set for the txs moving an asset, AssetId is -1 otherwise
+	AssetId int64
+	Amount  *big.Int
+	// NftIndex is set for the txs moving an nft, it is -1 otherwise
+	NftIndex int64
+}
+
+// AccountEventHandler handles the events of an AccountSubscription. An error stops the
+// subscription.
+type AccountEventHandler func(ctx context.Context, event *AccountEvent) error
+
+type accountSubscriptionOption struct {
+	pollInterval time.Duration
+}
+
+type AccountSubscriptionOptionFunc func(*accountSubscriptionOption)
+
+// SubscribeWithPollInterval sets the delay between two polls, it is 1s by default.
+func SubscribeWithPollInterval(interval time.Duration) AccountSubscriptionOptionFunc {
+	return func(o *accountSubscriptionOption) {
+		o.pollInterval = interval
+	}
+}
+
+// AccountSubscription emits the activity of an account as typed events. It polls the txs of
+// the account, see GetTxsByAccountIndex, and its pending txs, see GetPendingTxsByL1Address,
+// and emits an event each time a tx reaches a higher status, from pending to executed,
+// packed, committed and verified, or failed. The statuses are deduplicated across the two
+// sources, and statuses skipped between two polls are not emitted:
+//
+//	subscription := client.NewAccountSubscriptionByL1Address(sdkClient, l1Address)
+//	err := subscription.Run(ctx, func(ctx context.Context, event *client.AccountEvent) error {
+//		if event.Type == client.AccountEventTransferIn && event.Status == types.TxStatusVerified {
+//			credit(event.Counterparty, event.AssetId, event.Amount)
+//		}
+//		return nil
+//	})
+//
+// On start, the txs which are not verified or failed yet are emitted with their current
+// status, the older ones are not emitted.
+type AccountSubscription struct {
+	client       ZkBNBContextQuerier
+	option       *accountSubscriptionOption
+	accountIndex int64
+	l1Address    string
+	// statuses are the last emitted statuses of the txs by hash
+	statuses map[string]int64
+}
+
+// NewAccountSubscriptionByIndex subscribes to the activity of the account of the given index.
+func NewAccountSubscriptionByIndex(client ZkBNBContextQuerier, accountIndex int64, options ...AccountSubscriptionOptionFunc) *AccountSubscription {
+	return newAccountSubscription(client, accountIndex, "", options)
+}
+
+// NewAccountSubscriptionByL1Address subscribes to the activity of the account of the given L1
+// address. The account may not exist yet, e.g. before its first deposit.
+func NewAccountSubscriptionByL1Address(client ZkBNBContextQuerier, l1Address string, options ...AccountSubscriptionOptionFunc) *AccountSubscription {
+	return newAccountSubscription(client, -1, l1Address, options)
+}
+
+func newAccountSubscription(client ZkBNBContextQuerier, accountIndex int64, l1Address string, options []AccountSubscriptionOptionFunc) *AccountSubscription {
+	opt := &accountSubscriptionOption{
+		pollInterval: defaultFollowPollInterval,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	return &AccountSubscription{
+		client:       client,
+		option:       opt,
+		accountIndex: accountIndex,
+		l1Address:    l1Address,
+	}
+}
+
+// Run emits the events to the handler until the context is cancelled or an error occurs.
+// Transient api errors are retried at the next poll.
+func (s *AccountSubscription) Run(ctx context.Context, handler AccountEventHandler) error {
+	for {
+		err := s.poll(ctx, handler)
+		if err != nil && (ctx.Err() != nil || !IsRetryable(err)) {
+			return err
+		}
+		if err := sleepContext(ctx, s.option.pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// poll fetches the txs of the account and emits the status changes.
+func (s *AccountSubscription) poll(ctx context.Context, handler AccountEventHandler) error {
+	if err := s.resolveAccount(ctx); err != nil {
+		return err
+	}
+	first := s.statuses == nil
+
+	// txs is in chronological order, the sources list the newest first
+	var txs []*types.Tx
+	statuses := make(map[string]int64)
+	see := func(tx *types.Tx) {
+		status, ok := statuses[tx.Hash]
+		if !ok {
+			txs = append(txs, tx)
+		}
+		if !ok || status < tx.Status {
+			statuses[tx.Hash] = tx.Status
+		}
+	}
+	if s.accountIndex >= 0 {
+		accountTxs, err := s.accountTxs(ctx, first)
+		if err != nil {
+			return err
+		}
+		for i := len(accountTxs) - 1; i >= 0; i-- {
+			see(accountTxs[i])
+		}
+	}
+	if s.l1Address != "" {
+		_, pendingTxs, err := s.client.GetPendingTxsByL1AddressWithContext(ctx, s.l1Address)
+		// the pending txs of an account unknown to ZkBNB are rejected
+		if err != nil && !isRejected(err) {
+			return err
+		}
+		for i := len(pendingTxs) - 1; i >= 0; i-- {
+			see(pendingTxs[i])
+		}
+	}
+
+	for _, tx := range txs {
+		status := statuses[tx.Hash]
+		last, known := s.statuses[tx.Hash]
+		if known && (status <= last || last == types.TxStatusFailed) {
+			statuses[tx.Hash] = last
+			continue
+		}
+		if first && (status == types.TxStatusVerified || status == types.TxStatusFailed) {
+			continue
+		}
+		event := s.event(tx)
+		event.Status = status
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	// the txs which were not seen are older than the last verified one and will not change
+	s.statuses = statuses
+	return nil
+}
+
+// accountTxs returns the txs of the account, newest first, down to the first page with a
+// verified tx which was already seen verified, or any verified tx on the first poll.
+func (s *AccountSubscription) accountTxs(ctx context.Context, first bool) ([]*types.Tx, error) {
+	var result []*types.Tx
+	for offset := uint32(0); ; offset += defaultPageSize {
+		total, txs, err := s.client.GetTxsByAccountIndexWithContext(ctx, s.accountIndex, offset, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, txs...)
+		for _, tx := range txs {
+			if tx.Status == types.TxStatusVerified && (first || s.statuses[tx.Hash] == types.TxStatusVerified) {
+				return result, nil
+			}
+		}
+		if len(txs) < defaultPageSize || offset+uint32(len(txs)) >= total {
+			return result, nil
+		}
+	}
+}
+
+// resolveAccount looks up the index or the L1 address of the account, whichever is unknown.
+func (s *AccountSubscription) resolveAccount(ctx context.Context) error {
+	switch {
+	case s.accountIndex < 0:
+		account, err := s.client.GetAccountByL1AddressWithContext(ctx, s.l1Address)
+		if isRejected(err) {
+			// only the pending txs are polled until the account exists
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.accountIndex = account.Index
+	case s.l1Address == "":
+		account, err := s.client.GetAccountByIndexWithContext(ctx, s.accountIndex)
+		if err != nil {
+			return err
+		}
+		s.l1Address = account.L1Address
+	}
+	return nil
+}
+
+func (s *AccountSubscription) isSender(tx *types.Tx) bool {
+	if s.accountIndex >= 0 && tx.AccountIndex == s.accountIndex {
+		return true
+	}
+	return strings.EqualFold(tx.L1Address, s.l1Address)
+}
+
+// event types the tx from the point of view of the account.
+func (s *AccountSubscription) event(tx *types.Tx) *AccountEvent {
+	event := &AccountEvent{Tx: tx, AssetId: -1, NftIndex: -1}
+	setAmount := func() {
+		event.AssetId = tx.AssetId
+		event.Amount, _ = new(big.Int).SetString(tx.Amount, 10)
+	}
+	sender := s.isSender(tx)
+	switch tx.Type {
+	case types.TxTypeTransfer:
+		setAmount()
+		event.Type, event.Counterparty = AccountEventTransferIn, tx.FromL1Address
+		if sender {
+			event.Type, event.Counterparty = AccountEventTransferOut, tx.ToL1Address
+		}
+	case types.TxTypeDeposit:
+		setAmount()
+		event.Type = AccountEventDeposit
+	case types.TxTypeWithdraw:
+		setAmount()
+		event.Type = AccountEventWithdraw
+	case types.TxTypeFullExit:
+		setAmount()
+		event.Type = AccountEventFullExit
+	case types.TxTypeDepositNft:
+		event.Type, event.NftIndex = AccountEventDepositNft, tx.NftIndex
+	case types.TxTypeWithdrawNft:
+		event.Type, event.NftIndex = AccountEventWithdrawNft, tx.NftIndex
+	case types.TxTypeFullExitNft:
+		event.Type, event.NftIndex = AccountEventFullExitNft, tx.NftIndex
+	case types.TxTypeChangePubKey:
+		event.Type = AccountEventChangePubKey
+	case types.TxTypeCreateCollection:
+		event.Type = AccountEventCreateCollection
+	case types.TxTypeMintNft:
+		event.Type, event.NftIndex, event.Counterparty = AccountEventMintNft, tx.NftIndex, tx.FromL1Address
+		if sender {
+			event.Counterparty = tx.ToL1Address
+		}
+	case types.TxTypeTransferNft:
+		event.Type, event.NftIndex, event.Counterparty = AccountEventTransferNftIn, tx.NftIndex, tx.FromL1Address
+		if sender {
+			event.Type, event.Counterparty = AccountEventTransferNftOut, tx.ToL1Address
+		}
+	case types.TxTypeCancelOffer:
+		event.Type = AccountEventCancelOffer
+	case types.TxTypeAtomicMatch:
+		setAmount()
+		event.Type, event.NftIndex = AccountEventAtomicMatch, tx.NftIndex
+	}
+	return event
+}
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/bnb-chain/zkbnb-eth-rpc/core"
 	"github.com/bnb-chain/zkbnb-eth-rpc/rpc"
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
@@ -38,6 +40,11 @@ func GetTxWithFromHash(hash string) GetTxOptionFunc {
 }
 
 type ZkBNBQuerier interface {
+	ZkBNBContextQuerier
+	ZkBNBQueryIterator
+	ZkBNBTxTracker
+	ZkBNBCacheInvalidator
+
 	// GetCurrentHeight returns current block height
 	GetCurrentHeight() (int64, error)
 
@@ -136,11 +143,17 @@ type ZkBNBQuerier interface {
 }
 
 type ZkBNBTxSender interface {
+	ZkBNBContextTxSender
+	ZkBNBL1SignModeNegotiator
 
 	// KeyManager returns the key manager for signing txs.
 	KeyManager() accounts.KeyManager
 
-	// SendRawTx sends signed raw transaction and returns tx hash
+	// NonceManager returns the local nonce manager, nil unless the client was created with WithNonceManager.
+	NonceManager() *NonceManager
+
+	// SendRawTx sends signed raw transaction and returns tx hash, the L1 signature is taken as a
+	// personal signature unless the context of SendRawTxWithContext says otherwise, see WithL1SignMode
 	SendRawTx(txType uint32, txInfo string) (string, error)
 
 	// ChangePubKey will sign tx with key manager and send signed transaction
@@ -180,9 +193,14 @@ type ZkBNBTxSender interface {
 }
 
 type ZkBNBL1Client interface {
+	ZkBNBContextL1Client
+
 	// SetPrivateKey will set the private key of the l1 account
 	SetPrivateKey(pk string) error
 
+	// SetKeystore will set the private key of the l1 account from an Ethereum V3 keystore file
+	SetKeystore(path, passphrase string) error
+
 	// DepositBNB will deposit specific amount bnb to l2
 	DepositBNB(l1Address string, amount *big.Int) (*types2.Transaction, error)
 
@@ -199,14 +217,82 @@ type ZkBNBL1Client interface {
 	RequestFullExitNft(accountIndex uint32, nftIndex uint32) (*types2.Transaction, error)
 }
 
+// ZkBNBContextQuerier is the context-aware variant of ZkBNBQuerier. The context is
+// applied to the underlying http request, so cancelling it aborts an in-flight query.
+type ZkBNBContextQuerier interface {
+	GetCurrentHeightWithContext(ctx context.Context) (int64, error)
+	GetBlocksWithContext(ctx context.Context, offset, limit int64) (uint32, []*types.Block, error)
+	GetBlockByHeightWithContext(ctx context.Context, blockHeight int64) (*types.Block, error)
+	GetBlockByCommitmentWithContext(ctx context.Context, blockCommitment string) (*types.Block, error)
+	GetTxWithContext(ctx context.Context, hash string) (*types.EnrichedTx, error)
+	GetTxsByL1AddressWithContext(ctx context.Context, l1Address string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+	GetTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error)
+	GetTxsByAccountIndexWithContext(ctx context.Context, accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+	GetTxsByBlockHeightWithContext(ctx context.Context, blockHeight uint32) ([]*types.Tx, error)
+	GetPendingTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error)
+	GetPendingTxsByL1AddressWithContext(ctx context.Context, l1Address string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+	GetExecutedTxsWithContext(ctx context.Context, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+	GetAccountByL1AddressWithContext(ctx context.Context, l1Address string) (*types.Account, error)
+	GetAccountsWithContext(ctx context.Context, offset, limit uint32) (*types.Accounts, error)
+	GetAccountByIndexWithContext(ctx context.Context, accountIndex int64) (*types.Account, error)
+	GetNextNonceWithContext(ctx context.Context, accountIndex int64) (int64, error)
+	GetMaxOfferIdWithContext(ctx context.Context, accountIndex int64) (uint64, error)
+	GetAssetByIdWithContext(ctx context.Context, id uint32) (*types.Asset, error)
+	GetAssetBySymbolWithContext(ctx context.Context, symbol string) (*types.Asset, error)
+	GetAssetsWithContext(ctx context.Context, offset, limit uint32) (*types.Assets, error)
+	GetGasFeeAssetsWithContext(ctx context.Context) (*types.GasFeeAssets, error)
+	GetGasFeeWithContext(ctx context.Context, assetId int64, txType int) (*big.Int, error)
+	GetProtocolRateWithContext(ctx context.Context) (int64, error)
+	SearchWithContext(ctx context.Context, keyword string) (*types.Search, error)
+	GetLayer2BasicInfoWithContext(ctx context.Context) (*types.Layer2BasicInfo, error)
+	GetGasAccountWithContext(ctx context.Context) (*types.GasAccount, error)
+	GetNftsByAccountIndexWithContext(ctx context.Context, accountIndex, offset, limit int64) (*types.Nfts, error)
+	GetNftByNftIndexWithContext(ctx context.Context, nftIndex int64) (*types.Nft, error)
+	GetRollbacksWithContext(ctx context.Context, fromBlockHeight, offset, limit int64) (total uint32, rollbacks []*types.Rollback, err error)
+	GetMaxCollectionIdWithContext(ctx context.Context, accountIndex int64) (*types.MaxCollectionId, error)
+	GetNftByTxHashWithContext(ctx context.Context, txHash string) (*types.NftIndex, error)
+	UpdateNftByIndexWithContext(ctx context.Context, nft *types.UpdateNftReq, signatureList ...string) (*types.Mutable, error)
+}
+
+// ZkBNBContextTxSender is the context-aware variant of ZkBNBTxSender. The context also
+// covers the lookups used to fill default TransactOpts (gas account, nonce and gas fee).
+type ZkBNBContextTxSender interface {
+	SendRawTxWithContext(ctx context.Context, txType uint32, txInfo string) (string, error)
+	ChangePubKeyWithContext(ctx context.Context, tx *types.ChangePubKeyReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	GenerateSignBodyWithContext(ctx context.Context, txData interface{}, ops *types.TransactOpts) (string, error)
+	GenerateSignatureWithContext(ctx context.Context, privateKey string, txData interface{}, ops *types.TransactOpts) (string, error)
+	MintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	CreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	CancelOfferWithContext(ctx context.Context, tx *types.CancelOfferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	AtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (string, error)
+	WithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	TransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	TransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+	WithdrawWithContext(ctx context.Context, tx *types.WithdrawTxReq, ops *types.TransactOpts, signatureList ...string) (string, error)
+}
+
+// ZkBNBContextL1Client is the context-aware variant of ZkBNBL1Client. The context is
+// used for the nonce, chain id and gas price lookups as well as for sending the tx.
+type ZkBNBContextL1Client interface {
+	DepositBNBWithContext(ctx context.Context, l1Address string, amount *big.Int) (*types2.Transaction, error)
+	DepositBEP20WithContext(ctx context.Context, token common.Address, l1Address string, amount *big.Int) (*types2.Transaction, error)
+	DepositNftWithContext(ctx context.Context, nftL1Address common.Address, l1Address string, nftL1TokenId *big.Int) (*types2.Transaction, error)
+	RequestFullExitWithContext(ctx context.Context, accountIndex uint32, asset common.Address) (*types2.Transaction, error)
+	RequestFullExitNftWithContext(ctx context.Context, accountIndex uint32, nftIndex uint32) (*types2.Transaction, error)
+}
+
 func NewZkBNBClientWithPrivateKey(url, privateKey string, chainId uint64, channelNames ...string) (ZkBNBClient, error) {
-	if len(channelNames) > 1 {
-		return nil, errors.New("the passed channelName contains more than one channelName value and it is illegal")
-	}
-	channelName := ""
-	if len(channelNames) == 1 {
-		channelName = channelNames[0]
+	channelName, err := parseChannelNames(channelNames)
+	if err != nil {
+		return nil, err
 	}
+	return NewZkBNBClientWithPrivateKeyAndOptions(url, privateKey, chainId, WithChannelName(channelName))
+}
+
+// NewZkBNBClientWithPrivateKeyAndOptions is NewZkBNBClientWithPrivateKey configured with
+// options, e.g. WithTLSConfig or WithRetryPolicy.
+func NewZkBNBClientWithPrivateKeyAndOptions(url, privateKey string, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	opt := newClientOption(options)
 	l1Signer, err := signer.NewL1Singer(privateKey)
 	if err != nil {
 		return nil, err
@@ -220,52 +306,151 @@ func NewZkBNBClientWithPrivateKey(url, privateKey string, chainId uint64, channe
 		return nil, err
 	}
 
-	return &l2Client{
-		endpoint:    url,
-		privateKey:  privateKey,
-		address:     l1Signer.GetAddress(),
-		chainId:     chainId,
-		channelName: channelName,
-		l1Signer:    l1Signer,
-		keyManager:  keyManager,
-	}, nil
+	client := &l2Client{
+		endpoint:   url,
+		privateKey: privateKey,
+		address:    l1Signer.GetAddress(),
+		chainId:    chainId,
+		l1Signer:   l1Signer,
+		keyManager: keyManager,
+	}
+	opt.apply(client)
+	return client, nil
 }
 
-func NewZkBNBClientNoAuthorized(url, seed, address string, chainId uint64, channelNames ...string) (ZkBNBClient, error) {
-	if len(channelNames) > 1 {
-		return nil, errors.New("the passed channelName contains more than one channelName value and it is illegal")
+// NewZkBNBClientWithL1Signer creates a client signing with the given L1 signer in place of a
+// private key, e.g. a signer.RemoteL1Signer. The L2 key is derived from a signature of the
+// L1 signer like NewZkBNBClientWithPrivateKey does, which fails with
+// accounts.ErrNondeterministicSeed for signers whose signatures are not reproducible.
+func NewZkBNBClientWithL1Signer(url string, l1Signer signer.L1Signer, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	opt := newClientOption(options)
+	seed, err := accounts.GenerateSeedWithSigner(l1Signer, chainId)
+	if err != nil {
+		return nil, err
 	}
-	channelName := ""
-	if len(channelNames) == 1 {
-		channelName = channelNames[0]
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &l2Client{
+		endpoint:   url,
+		privateKey: "",
+		address:    l1Signer.GetAddress(),
+		chainId:    chainId,
+		l1Signer:   l1Signer,
+		keyManager: keyManager,
+	}
+	opt.apply(client)
+	return client, nil
+}
+
+// NewZkBNBClientWithKeyManager creates a client signing the l2 txs with the given key manager,
+// e.g. one from accounts.NewPrivateKeyManager or accounts.NewRemoteKeyManager, and the L1
+// signatures with the L1 signer.
+func NewZkBNBClientWithKeyManager(url string, keyManager accounts.KeyManager, l1Signer signer.L1Signer, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	if keyManager == nil || l1Signer == nil {
+		return nil, errors.New("key manager and l1 signer must be set")
+	}
+	opt := newClientOption(options)
+
+	client := &l2Client{
+		endpoint:   url,
+		privateKey: "",
+		address:    l1Signer.GetAddress(),
+		chainId:    chainId,
+		l1Signer:   l1Signer,
+		keyManager: keyManager,
 	}
+	opt.apply(client)
+	return client, nil
+}
+
+// NewZkBNBClientWithKeystore creates a client with the L1 key of an Ethereum V3 keystore file,
+// the L2 key is derived from it like NewZkBNBClientWithPrivateKey does.
+func NewZkBNBClientWithKeystore(url, keystorePath, passphrase string, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	l1Signer, err := accounts.NewL1SignerFromKeystore(keystorePath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewZkBNBClientWithL1Signer(url, l1Signer, chainId, options...)
+}
+
+// NewZkBNBClientWithSeedKeystore creates a client with the L2 seed of a keystore file written
+// by accounts.SaveSeedKeystore. Like NewZkBNBClientNoAuthorized, the client has no L1 key and
+// the L1 signatures have to be passed to the tx methods.
+func NewZkBNBClientWithSeedKeystore(url, keystorePath, passphrase string, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	seed, address, err := accounts.LoadSeedKeystore(keystorePath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewZkBNBClientNoAuthorizedWithOptions(url, seed, address, chainId, options...)
+}
+
+func NewZkBNBClientNoAuthorized(url, seed, address string, chainId uint64, channelNames ...string) (ZkBNBClient, error) {
+	channelName, err := parseChannelNames(channelNames)
+	if err != nil {
+		return nil, err
+	}
+	return NewZkBNBClientNoAuthorizedWithOptions(url, seed, address, chainId, WithChannelName(channelName))
+}
+
+// NewZkBNBClientNoAuthorizedWithOptions is NewZkBNBClientNoAuthorized configured with options.
+func NewZkBNBClientNoAuthorizedWithOptions(url, seed, address string, chainId uint64, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	opt := newClientOption(options)
 	keyManager, err := accounts.NewSeedKeyManager(seed)
 	if err != nil {
 		return nil, err
 	}
 
-	return &l2Client{
-		endpoint:    url,
-		privateKey:  "",
-		address:     address,
-		chainId:     chainId,
-		channelName: channelName,
-		l1Signer:    nil,
-		keyManager:  keyManager,
-	}, nil
+	client := &l2Client{
+		endpoint:   url,
+		privateKey: "",
+		address:    address,
+		chainId:    chainId,
+		l1Signer:   nil,
+		keyManager: keyManager,
+	}
+	opt.apply(client)
+	return client, nil
+}
+
+func parseChannelNames(channelNames []string) (string, error) {
+	if len(channelNames) > 1 {
+		return "", errors.New("the passed channelName contains more than one channelName value and it is illegal")
+	}
+	if len(channelNames) == 1 {
+		return channelNames[0], nil
+	}
+	return "", nil
 }
 
 func NewZkBNBL1Client(provider, zkbnbContract string) (ZkBNBL1Client, error) {
 	bscClient, err := rpc.NewClient(provider)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	zkbnbContractInstance, err := core.NewZkBNB(common.HexToAddress(zkbnbContract), bscClient)
+	l1Client, err := newL1Client(bscClient, zkbnbContract)
 	if err != nil {
-		panic("new proxy contract error")
+		return nil, err
 	}
+	l1Client.ProviderClient = bscClient
+	return l1Client, nil
+}
 
+// NewZkBNBL1ClientWithBackend creates an L1 client on top of the given backend instead of
+// dialing an rpc provider, e.g. a go-ethereum simulated backend.
+func NewZkBNBL1ClientWithBackend(backend L1Backend, zkbnbContract string) (ZkBNBL1Client, error) {
+	return newL1Client(backend, zkbnbContract)
+}
+
+func newL1Client(backend L1Backend, zkbnbContract string) (*L1Client, error) {
+	zkbnbContractInstance, err := core.NewZkBNB(common.HexToAddress(zkbnbContract), backend)
+	if err != nil {
+		return nil, fmt.Errorf("new proxy contract error: %v", err)
+	}
 	return &L1Client{
-		bscClient, zkbnbContractInstance, nil,
+		ZkbnbContractInstance: zkbnbContractInstance,
+		backend:               backend,
 	}, nil
 }
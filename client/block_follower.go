@@ -0,0 +1,535 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const (
+	defaultFollowPollInterval = time.Second
+	defaultFollowPrefetch     = 8
+	// followRevertHistory is the number of handled blocks kept for the revert handler
+	followRevertHistory = 128
+)
+
+// Checkpoint is the cursor of a BlockFollower, the last block it handled.
+type Checkpoint struct {
+	Height     int64  `json:"height"`
+	Commitment string `json:"commitment"`
+	// RollbackID is the id of the last rollback applied by a follower watching rollbacks
+	RollbackID uint `json:"rollback_id,omitempty"`
+}
+
+// CheckpointStore persists the checkpoint of a BlockFollower, so that a restarted follower
+// resumes after the last handled block.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the saved checkpoint, nil if none was saved yet
+	LoadCheckpoint(ctx context.Context) (*Checkpoint, error)
+	// SaveCheckpoint saves the checkpoint, replacing the previous one
+	SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory, it is the default store of a
+// BlockFollower. It is safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+func (s *MemoryCheckpointStore) LoadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	cp := *s.checkpoint
+	return &cp, nil
+}
+
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *checkpoint
+	s.checkpoint = &cp
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint as JSON in a file. The file is replaced
+// atomically, a crash never leaves a partially written checkpoint.
+type FileCheckpointStore struct {
+	path string
+}
+
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+func (s *FileCheckpointStore) LoadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", s.path, err)
+	}
+	return checkpoint, nil
+}
+
+func (s *FileCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// BlockHandler handles a block emitted by a BlockFollower, its Txs are set. An error stops
+// the follower before the block is checkpointed, the block is emitted again on restart.
+type BlockHandler func(ctx context.Context, block *types.Block) error
+
+// BlockRevert reports a block undone by a rollback of ZkBNB, along with its txs.
+type BlockRevert struct {
+	// Rollback is the rollback which undid the block
+	Rollback *types.Rollback
+	// Height is the height of the reverted block
+	Height int64
+	// Block is the block as it was emitted, with its txs. It is nil when the follower no
+	// longer holds it, e.g. when it was emitted before a restart.
+	Block *types.Block
+}
+
+// RevertHandler handles a block reverted by a rollback. The reverts are emitted from the
+// highest block down, and the checkpoint is rewound below each handled revert.
+type RevertHandler func(ctx context.Context, revert *BlockRevert) error
+
+type blockFollowerOption struct {
+	fromHeight    int64
+	store         CheckpointStore
+	pollInterval  time.Duration
+	prefetch      int
+	revertHandler RevertHandler
+}
+
+type BlockFollowerOptionFunc func(*blockFollowerOption)
+
+// FollowFromHeight sets the height of the first block to emit when the checkpoint store is
+// empty. By default the follower starts after the current height and only emits new blocks.
+func FollowFromHeight(height int64) BlockFollowerOptionFunc {
+	return func(o *blockFollowerOption) {
+		o.fromHeight = height
+	}
+}
+
+// FollowWithCheckpointStore sets the store of the follower checkpoint, a
+// MemoryCheckpointStore by default. The follower resumes after the saved checkpoint.
+func FollowWithCheckpointStore(store CheckpointStore) BlockFollowerOptionFunc {
+	return func(o *blockFollowerOption) {
+		o.store = store
+	}
+}
+
+// FollowWithPollInterval sets the delay between two polls once the follower caught up with
+// the chain, and between two attempts after a transient error. It is 1s by default.
+func FollowWithPollInterval(interval time.Duration) BlockFollowerOptionFunc {
+	return func(o *blockFollowerOption) {
+		o.pollInterval = interval
+	}
+}
+
+// FollowWithPrefetch sets how many blocks are fetched ahead of the handler, 8 by default.
+// Fetching pauses while the handler is that many blocks behind.
+func FollowWithPrefetch(blocks int) BlockFollowerOptionFunc {
+	return func(o *blockFollowerOption) {
+		o.prefetch = blocks
+	}
+}
+
+// FollowRollbacks makes the follower watch the rollbacks of ZkBNB, see GetRollbacks. When
+// a rollback undoes handled blocks, the follower emits a revert for each of them to the
+// handler, rewinds its checkpoint below the rollback and emits the new blocks from there.
+// The rollbacks are polled at the poll interval, blocks handled in between are reverted
+// afterwards. The rollbacks which happened before the first start of the follower are
+// ignored, as are the ones which happened before it resumed a checkpoint saved without
+// watching the rollbacks.
+func FollowRollbacks(handler RevertHandler) BlockFollowerOptionFunc {
+	return func(o *blockFollowerOption) {
+		o.revertHandler = handler
+	}
+}
+
+// BlockFollower emits the L2 blocks in height order, each one with its txs, and checkpoints
+// every handled block:
+//
+//	follower := client.NewBlockFollower(sdkClient, client.FollowWithCheckpointStore(store))
+//	err := follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+//		return index(block)
+//	})
+//
+// Blocks which are not available yet, e.g. behind a gap in the api, are polled until they
+// are, no height is ever skipped. Transient api errors are retried.
+type BlockFollower struct {
+	client ZkBNBContextQuerier
+	option *blockFollowerOption
+}
+
+func NewBlockFollower(client ZkBNBContextQuerier, options ...BlockFollowerOptionFunc) *BlockFollower {
+	opt := &blockFollowerOption{
+		store:        NewMemoryCheckpointStore(),
+		pollInterval: defaultFollowPollInterval,
+		prefetch:     defaultFollowPrefetch,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	if opt.prefetch <= 0 {
+		opt.prefetch = 1
+	}
+	return &BlockFollower{client: client, option: opt}
+}
+
+// Checkpoint returns the saved checkpoint, nil if no block was handled yet.
+func (f *BlockFollower) Checkpoint(ctx context.Context) (*Checkpoint, error) {
+	return f.option.store.LoadCheckpoint(ctx)
+}
+
+// Run emits the blocks to the handler until the context is cancelled or an error occurs.
+// It returns the context error or the error which stopped it.
+func (f *BlockFollower) Run(ctx context.Context, handler BlockHandler) error {
+	checkpoint, err := f.start(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rollbackTicks <-chan time.Time
+	if f.option.revertHandler != nil {
+		ticker := time.NewTicker(f.option.pollInterval)
+		defer ticker.Stop()
+		rollbackTicks = ticker.C
+	}
+	// history holds the last handled blocks, to be passed to the revert handler
+	var history []*types.Block
+	fetcher := f.startFetcher(ctx, checkpoint.Height+1)
+	defer func() {
+		fetcher.stop()
+	}()
+
+	for {
+		select {
+		case block, ok := <-fetcher.blocks:
+			if !ok {
+				return fetcher.err(ctx)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := handler(ctx, block); err != nil {
+				return err
+			}
+			checkpoint = &Checkpoint{Height: block.Height, Commitment: block.Commitment, RollbackID: checkpoint.RollbackID}
+			if err := f.option.store.SaveCheckpoint(ctx, checkpoint); err != nil {
+				return fmt.Errorf("save checkpoint at height %d: %w", block.Height, err)
+			}
+			if f.option.revertHandler != nil {
+				history = append(history, block)
+				if len(history) > followRevertHistory {
+					history = history[len(history)-followRevertHistory:]
+				}
+			}
+		case <-rollbackTicks:
+			rewound, err := f.applyRollbacks(ctx, checkpoint, &history)
+			if err != nil {
+				return err
+			}
+			if rewound != nil {
+				// the prefetched blocks may have been rolled back as well
+				fetcher.stop()
+				checkpoint = rewound
+				fetcher = f.startFetcher(ctx, checkpoint.Height+1)
+			}
+		}
+	}
+}
+
+// start returns the checkpoint to resume from, the one before the first block to emit when
+// the store has none.
+func (f *BlockFollower) start(ctx context.Context) (*Checkpoint, error) {
+	checkpoint, err := f.option.store.LoadCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint: %w", err)
+	}
+	if checkpoint != nil {
+		if f.option.revertHandler == nil || checkpoint.RollbackID != 0 {
+			return checkpoint, nil
+		}
+		// the checkpoint was saved without watching the rollbacks, the blocks it covers were
+		// handled after the rollbacks known by now
+		if checkpoint.RollbackID, err = f.lastRollbackID(ctx); err != nil {
+			return nil, err
+		}
+		if checkpoint.RollbackID != 0 {
+			if err := f.option.store.SaveCheckpoint(ctx, checkpoint); err != nil {
+				return nil, fmt.Errorf("save checkpoint at height %d: %w", checkpoint.Height, err)
+			}
+		}
+		return checkpoint, nil
+	}
+	checkpoint = &Checkpoint{}
+	if f.option.revertHandler != nil {
+		// the rollbacks which happened before the follower started are already applied
+		if checkpoint.RollbackID, err = f.lastRollbackID(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if f.option.fromHeight > 0 {
+		checkpoint.Height = f.option.fromHeight - 1
+		return checkpoint, nil
+	}
+	for {
+		current, err := f.client.GetCurrentHeightWithContext(ctx)
+		if err == nil {
+			checkpoint.Height = current
+			return checkpoint, nil
+		}
+		if err := f.retryable(ctx, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lastRollbackID returns the id of the last rollback, 0 if there was none.
+func (f *BlockFollower) lastRollbackID(ctx context.Context) (uint, error) {
+	for {
+		rollbacks, err := f.rollbacks(ctx, 0)
+		if err == nil {
+			var lastID uint
+			for _, rollback := range rollbacks {
+				if rollback.ID > lastID {
+					lastID = rollback.ID
+				}
+			}
+			return lastID, nil
+		}
+		if err := f.retryable(ctx, err); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// blockFetcher fetches blocks in the background.
+type blockFetcher struct {
+	blocks chan *types.Block
+	errs   chan error
+	cancel context.CancelFunc
+}
+
+func (f *BlockFollower) startFetcher(ctx context.Context, height int64) *blockFetcher {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	fetcher := &blockFetcher{
+		blocks: make(chan *types.Block, f.option.prefetch),
+		errs:   make(chan error, 1),
+		cancel: cancel,
+	}
+	go func() {
+		defer close(fetcher.blocks)
+		if err := f.fetch(fetchCtx, height, fetcher.blocks); err != nil {
+			fetcher.errs <- err
+		}
+	}()
+	return fetcher
+}
+
+// stop stops the fetcher and drops the prefetched blocks.
+func (fetcher *blockFetcher) stop() {
+	fetcher.cancel()
+	for range fetcher.blocks {
+	}
+}
+
+// err returns the error which stopped the fetcher.
+func (fetcher *blockFetcher) err(ctx context.Context) error {
+	select {
+	case err := <-fetcher.errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// fetch sends the blocks from the given height to the channel, it blocks while the channel
+// is full.
+func (f *BlockFollower) fetch(ctx context.Context, height int64, blocks chan<- *types.Block) error {
+	current := int64(-1)
+	for {
+		if height > current {
+			latest, err := f.client.GetCurrentHeightWithContext(ctx)
+			if err != nil {
+				if err := f.retryable(ctx, err); err != nil {
+					return err
+				}
+				continue
+			}
+			current = latest
+			if height > current {
+				if err := sleepContext(ctx, f.option.pollInterval); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		block, err := f.fetchBlock(ctx, height)
+		if err != nil {
+			// the block is below the current height but not served yet, it is polled again
+			if isRejected(err) {
+				err = sleepContext(ctx, f.option.pollInterval)
+			} else {
+				err = f.retryable(ctx, err)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		select {
+		case blocks <- block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		height++
+	}
+}
+
+// applyRollbacks emits the reverts of the handled blocks undone by the rollbacks which
+// happened since the checkpoint. It returns the rewound checkpoint, nil when there was no new
+// rollback.
+func (f *BlockFollower) applyRollbacks(ctx context.Context, checkpoint *Checkpoint, history *[]*types.Block) (*Checkpoint, error) {
+	rollbacks, err := f.rollbacks(ctx, checkpoint.RollbackID)
+	if err != nil {
+		// the rollbacks are polled again at the next tick
+		if IsRetryable(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(rollbacks) == 0 {
+		return nil, nil
+	}
+	lowest, lastID := rollbacks[0], rollbacks[0].ID
+	for _, rollback := range rollbacks {
+		if rollback.FromBlockHeight < lowest.FromBlockHeight {
+			lowest = rollback
+		}
+		if rollback.ID > lastID {
+			lastID = rollback.ID
+		}
+	}
+
+	rewound := *checkpoint
+	for rewound.Height >= lowest.FromBlockHeight && rewound.Height > 0 {
+		revert := &BlockRevert{Rollback: lowest, Height: rewound.Height}
+		blocks := *history
+		if len(blocks) > 0 && blocks[len(blocks)-1].Height == rewound.Height {
+			revert.Block = blocks[len(blocks)-1]
+			*history = blocks[:len(blocks)-1]
+		}
+		if err := f.option.revertHandler(ctx, revert); err != nil {
+			return nil, err
+		}
+		rewound.Height--
+		rewound.Commitment = ""
+		if blocks := *history; len(blocks) > 0 && blocks[len(blocks)-1].Height == rewound.Height {
+			rewound.Commitment = blocks[len(blocks)-1].Commitment
+		}
+		// the rollback is only marked as applied once all its reverts were handled
+		if err := f.option.store.SaveCheckpoint(ctx, &rewound); err != nil {
+			return nil, fmt.Errorf("save checkpoint at height %d: %w", rewound.Height, err)
+		}
+	}
+	rewound.RollbackID = lastID
+	if err := f.option.store.SaveCheckpoint(ctx, &rewound); err != nil {
+		return nil, fmt.Errorf("save checkpoint at height %d: %w", rewound.Height, err)
+	}
+	return &rewound, nil
+}
+
+// rollbacks returns the rollbacks with an id above the given one.
+func (f *BlockFollower) rollbacks(ctx context.Context, afterID uint) ([]*types.Rollback, error) {
+	var result []*types.Rollback
+	for offset := int64(0); ; offset += defaultPageSize {
+		total, rollbacks, err := f.client.GetRollbacksWithContext(ctx, 0, offset, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, rollback := range rollbacks {
+			if rollback.ID > afterID {
+				result = append(result, rollback)
+			}
+		}
+		if len(rollbacks) < defaultPageSize || offset+int64(len(rollbacks)) >= int64(total) {
+			return result, nil
+		}
+	}
+}
+
+func (f *BlockFollower) fetchBlock(ctx context.Context, height int64) (*types.Block, error) {
+	block, err := f.client.GetBlockByHeightWithContext(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Height != height {
+		return nil, fmt.Errorf("got block %d instead of block %d", block.Height, height)
+	}
+	txs, err := f.client.GetTxsByBlockHeightWithContext(ctx, uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	block.Txs = txs
+	return block, nil
+}
+
+// retryable waits for the poll interval when the error is transient, and returns it
+// otherwise.
+func (f *BlockFollower) retryable(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if !IsRetryable(err) {
+		return err
+	}
+	return sleepContext(ctx, f.option.pollInterval)
+}
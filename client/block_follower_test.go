@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// chainServer serves height blocks of one tx each, the blocks in missing are not found
+// until they were requested once.
+type chainServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	height  int64
+	missing map[int64]bool
+	fetched int32
+}
+
+func newChainServer(height int64, missing ...int64) *chainServer {
+	s := &chainServer{height: height, missing: make(map[int64]bool)}
+	for _, h := range missing {
+		s.missing[h] = true
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var result interface{}
+		value, _ := strconv.ParseInt(r.URL.Query().Get("value"), 10, 64)
+		switch r.URL.Path {
+		case "/api/v1/currentHeight":
+			result = &types.CurrentHeight{Height: s.height}
+		case "/api/v1/block":
+			if value > s.height || s.missing[value] {
+				delete(s.missing, value)
+				_, _ = w.Write([]byte(`{"code":23000,"message":"block not found"}`))
+				return
+			}
+			atomic.AddInt32(&s.fetched, 1)
+			result = &types.Block{Height: value, Commitment: strconv.FormatInt(value, 16)}
+		case "/api/v1/blockTxs":
+			result = &types.Txs{Total: 1, Txs: []*types.Tx{{Hash: "tx" + strconv.FormatInt(value, 10), BlockHeight: value}}}
+		}
+		body, _ := json.Marshal(result)
+		_, _ = w.Write(append([]byte(`{"code":100,`), body[1:]...))
+	}))
+	return s
+}
+
+func (s *chainServer) setHeight(height int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.height = height
+}
+
+func TestBlockFollower(t *testing.T) {
+	server := newChainServer(5, 3)
+	defer server.Close()
+	sdkClient, err := NewZkBNBClientNoAuthorized(server.URL, "", "", chainNetworkId)
+	assert.NoError(t, err)
+
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "follower", "checkpoint.json"))
+	follower := NewBlockFollower(sdkClient, FollowFromHeight(2), FollowWithCheckpointStore(store), FollowWithPollInterval(10*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var heights []int64
+	errStop := errors.New("stop")
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		heights = append(heights, block.Height)
+		assert.Equal(t, "tx"+strconv.FormatInt(block.Height, 10), block.Txs[0].Hash)
+		if block.Height == 5 {
+			server.setHeight(7)
+		}
+		if block.Height == 7 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	// the missing block 3 is waited for, and the failed block 7 is not checkpointed
+	assert.Equal(t, []int64{2, 3, 4, 5, 6, 7}, heights)
+	checkpoint, err := follower.Checkpoint(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Checkpoint{Height: 6, Commitment: "6"}, checkpoint)
+
+	// a new follower resumes from the checkpoint
+	follower = NewBlockFollower(sdkClient, FollowFromHeight(1), FollowWithCheckpointStore(NewFileCheckpointStore(store.path)))
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		assert.Equal(t, int64(7), block.Height)
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestBlockFollowerBackPressure(t *testing.T) {
+	server := newChainServer(100)
+	defer server.Close()
+	sdkClient, err := NewZkBNBClientNoAuthorized(server.URL, "", "", chainNetworkId)
+	assert.NoError(t, err)
+
+	follower := NewBlockFollower(sdkClient, FollowFromHeight(1), FollowWithPrefetch(2))
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+			if block.Height == 1 {
+				close(handled)
+				<-ctx.Done()
+			}
+			return nil
+		})
+	}()
+	<-handled
+	time.Sleep(100 * time.Millisecond)
+	// block 1 is being handled, blocks 2 and 3 are buffered and block 4 waits to be sent
+	assert.LessOrEqual(t, atomic.LoadInt32(&server.fetched), int32(4))
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// without start height the follower waits for new blocks
+	follower = NewBlockFollower(sdkClient, FollowWithPollInterval(10*time.Millisecond))
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		server.setHeight(101)
+	}()
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		assert.Equal(t, int64(101), block.Height)
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
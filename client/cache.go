@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// Cache is the storage backend of the client cache. Values are json encoded so that a
+// backend can be shared between processes, e.g. on top of redis. Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, false if there is none or it expired
+	Get(key string) ([]byte, bool)
+	// Set stores the value for key until ttl elapsed
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CacheKind identifies a group of cached values which can be invalidated together.
+type CacheKind int
+
+const (
+	// CacheGasAccount is the gas account returned by GetGasAccount
+	CacheGasAccount CacheKind = iota
+	// CacheAccountIndex maps L1 addresses to L2 account indexes
+	CacheAccountIndex
+	// CacheAssets are the assets returned by GetAssetById and GetAssetBySymbol
+	CacheAssets
+	// CacheGasFee are the gas fees returned by GetGasFee
+	CacheGasFee
+
+	cacheKinds
+)
+
+// ZkBNBCacheInvalidator drops values cached by the client.
+type ZkBNBCacheInvalidator interface {
+	// InvalidateCache drops the cached values of the given kinds, or all of them if no kind is given
+	InvalidateCache(kinds ...CacheKind)
+}
+
+// MemoryCache is an in-process Cache.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	sweepAt time.Time
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	// drop the expired entries from time to time so that invalidated values do not pile up
+	if now.After(m.sweepAt) {
+		for k, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+		m.sweepAt = now.Add(time.Minute)
+	}
+	m.entries[key] = memoryCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// clientCache namespaces the keys of a client in its backend. Invalidating a kind bumps its
+// generation, which is part of the keys, so the backend only needs Get and Set.
+type clientCache struct {
+	backend     Cache
+	ttl         time.Duration
+	namespace   string
+	generations [cacheKinds]uint64
+}
+
+func (cc *clientCache) key(kind CacheKind, key string) string {
+	return fmt.Sprintf("zkbnb/%s/%d/%d/%s", cc.namespace, kind, atomic.LoadUint64(&cc.generations[kind]), key)
+}
+
+func (c *l2Client) InvalidateCache(kinds ...CacheKind) {
+	if c.cache == nil {
+		return
+	}
+	if len(kinds) == 0 {
+		for kind := CacheKind(0); kind < cacheKinds; kind++ {
+			kinds = append(kinds, kind)
+		}
+	}
+	for _, kind := range kinds {
+		if kind >= 0 && kind < cacheKinds {
+			atomic.AddUint64(&c.cache.generations[kind], 1)
+		}
+	}
+}
+
+// cached decodes the cached value of key into result, or calls fetch to fill result and caches it.
+func (c *l2Client) cached(kind CacheKind, key string, result interface{}, fetch func() error) error {
+	if c.cache == nil {
+		return fetch()
+	}
+	cacheKey := c.cache.key(kind, key)
+	if value, ok := c.cache.backend.Get(cacheKey); ok && json.Unmarshal(value, result) == nil {
+		return nil
+	}
+	if err := fetch(); err != nil {
+		return err
+	}
+	if value, err := json.Marshal(result); err == nil {
+		c.cache.backend.Set(cacheKey, value, c.cache.ttl)
+	}
+	return nil
+}
+
+type accountIndex struct {
+	Index     int64  `json:"index"`
+	L1Address string `json:"l1_address"`
+}
+
+// getAccountIndexByL1Address resolves the index of the account, only the index and address
+// are cached since the rest of the account changes with every tx.
+func (c *l2Client) getAccountIndexByL1Address(ctx context.Context, l1Address string) (*accountIndex, error) {
+	result := &accountIndex{}
+	err := c.cached(CacheAccountIndex, l1Address, result, func() error {
+		account, err := c.GetAccountByL1AddressWithContext(ctx, l1Address)
+		if err != nil {
+			return err
+		}
+		result.Index, result.L1Address = account.Index, account.L1Address
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *l2Client) fetchGasAccount(ctx context.Context) (*types.GasAccount, error) {
+	res := &types.GasAccount{}
+	err := c.cached(CacheGasAccount, "", res, func() error {
+		return c.get(ctx, "/api/v1/gasAccount", res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *l2Client) fetchAsset(ctx context.Context, by, value string) (*types.Asset, error) {
+	result := &types.Asset{}
+	err := c.cached(CacheAssets, by+"/"+value, result, func() error {
+		return c.get(ctx, fmt.Sprintf("/api/v1/asset?by=%s&value=%s", by, value), result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *l2Client) fetchGasFee(ctx context.Context, assetId int64, txType int) (*big.Int, error) {
+	result := &types.GasFee{}
+	err := c.cached(CacheGasFee, fmt.Sprintf("%d/%d", assetId, txType), result, func() error {
+		return c.get(ctx, fmt.Sprintf("/api/v1/gasFee?asset_id=%d&tx_type=%d", assetId, txType), result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var price big.Int
+	price.SetString(result.GasFee, 10)
+	return &price, nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/gasAccount":
+			_, _ = w.Write([]byte(`{"code":100,"index":1,"l1_address":"0x1"}`))
+		case "/api/v1/gasFee":
+			_, _ = w.Write([]byte(`{"code":100,"gas_fee":"1000"}`))
+		case "/api/v1/asset":
+			_, _ = w.Write([]byte(`{"code":100,"id":1,"symbol":"BNB"}`))
+		}
+	}))
+	defer server.Close()
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithCache(nil, time.Hour))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		gasAccount, err := sdkClient.GetGasAccount()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), gasAccount.Index)
+		gasFee, err := sdkClient.GetGasFee(0, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, "1000", gasFee.String())
+		asset, err := sdkClient.GetAssetBySymbol("BNB")
+		assert.NoError(t, err)
+		assert.Equal(t, "BNB", asset.Symbol)
+	}
+	assert.Equal(t, 1, count("/api/v1/gasAccount"))
+	assert.Equal(t, 1, count("/api/v1/gasFee"))
+	assert.Equal(t, 1, count("/api/v1/asset"))
+
+	// another gas fee key is fetched separately
+	_, err = sdkClient.GetGasFee(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count("/api/v1/gasFee"))
+
+	sdkClient.InvalidateCache(CacheGasFee)
+	_, err = sdkClient.GetGasFee(0, 4)
+	assert.NoError(t, err)
+	_, err = sdkClient.GetGasAccount()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count("/api/v1/gasFee"))
+	assert.Equal(t, 1, count("/api/v1/gasAccount"))
+
+	sdkClient.InvalidateCache()
+	_, err = sdkClient.GetGasAccount()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count("/api/v1/gasAccount"))
+}
+
+type countingCache struct {
+	*MemoryCache
+	sets int32
+}
+
+func (c *countingCache) Set(key string, value []byte, ttl time.Duration) {
+	atomic.AddInt32(&c.sets, 1)
+	c.MemoryCache.Set(key, value, ttl)
+}
+
+func TestClientCacheBackend(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"code":100,"index":1,"l1_address":"0x1"}`))
+	}))
+	defer server.Close()
+
+	backend := &countingCache{MemoryCache: NewMemoryCache()}
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithCache(backend, 20*time.Millisecond))
+	assert.NoError(t, err)
+
+	_, err = sdkClient.GetGasAccount()
+	assert.NoError(t, err)
+	_, err = sdkClient.GetGasAccount()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&backend.sets))
+
+	// expired values are fetched again
+	time.Sleep(30 * time.Millisecond)
+	_, err = sdkClient.GetGasAccount()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// errors are not cached
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":25000,"message":"asset not found"}`))
+	})
+	_, err = sdkClient.GetAssetById(7)
+	assert.ErrorIs(t, err, &APIError{Code: 25000})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&backend.sets))
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultMaxHeightLag        = 10
+)
+
+type endpointState struct {
+	url      string
+	height   int64
+	latency  time.Duration
+	failedAt time.Time
+}
+
+// endpointPool keeps track of the health of several ZkBNB api endpoints. The endpoints are
+// health checked with GetCurrentHeight at most once per interval, the check is triggered by
+// the requests themselves so that no background goroutine outlives the client. Without an
+// interval the endpoints are not health checked, they are only ranked by their failures.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	interval  time.Duration
+	// checkTimeout bounds a single health check
+	checkTimeout time.Duration
+	// failedFor is how long a failed endpoint is avoided
+	failedFor time.Duration
+	maxLag    int64
+	checkedAt time.Time
+	checking  bool
+
+	check func(ctx context.Context, endpoint string) (int64, error)
+}
+
+func newEndpointPool(urls []string, interval, requestTimeout time.Duration, maxLag int64) *endpointPool {
+	pool := &endpointPool{
+		interval:     interval,
+		checkTimeout: requestTimeout,
+		failedFor:    interval,
+		maxLag:       maxLag,
+	}
+	if interval <= 0 {
+		pool.failedFor = defaultHealthCheckInterval
+	} else if requestTimeout <= 0 || interval < requestTimeout {
+		pool.checkTimeout = interval
+	}
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &endpointState{url: url})
+	}
+	return pool
+}
+
+// candidates returns the endpoints ordered from the healthiest to the least healthy one.
+// Endpoints which failed recently or lag behind the highest known height come last.
+func (p *endpointPool) candidates() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.interval > 0 && !p.checking && time.Since(p.checkedAt) >= p.interval {
+		p.checking = true
+		go p.refresh()
+	}
+
+	var maxHeight int64
+	for _, e := range p.endpoints {
+		if e.height > maxHeight {
+			maxHeight = e.height
+		}
+	}
+	now := time.Now()
+	rank := func(e *endpointState) int {
+		switch {
+		case !e.failedAt.IsZero() && now.Sub(e.failedAt) < p.failedFor:
+			return 2
+		case maxHeight-e.height > p.maxLag:
+			return 1
+		default:
+			return 0
+		}
+	}
+	sorted := make([]*endpointState, len(p.endpoints))
+	copy(sorted, p.endpoints)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i]), rank(sorted[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].latency < sorted[j].latency
+	})
+	urls := make([]string, 0, len(sorted))
+	for _, e := range sorted {
+		urls = append(urls, e.url)
+	}
+	return urls
+}
+
+func (p *endpointPool) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.checkTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpointState) {
+			defer wg.Done()
+			start := time.Now()
+			height, err := p.check(ctx, e.url)
+			latency := time.Since(start)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if err != nil {
+				e.failedAt = time.Now()
+				return
+			}
+			e.height = height
+			e.latency = latency
+			e.failedAt = time.Time{}
+		}(e)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.checkedAt = time.Now()
+	p.checking = false
+	p.mu.Unlock()
+}
+
+func (p *endpointPool) markFailed(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.endpoints {
+		if e.url == url {
+			e.failedAt = time.Now()
+		}
+	}
+}
+
+// withEndpoint calls call with the best endpoint. With failover, endpoints failing with a
+// transient error are skipped and the call is repeated on the next one.
+func (c *l2Client) withEndpoint(failover bool, call func(endpoint string) error) error {
+	if c.endpoints == nil {
+		return call(c.endpoint)
+	}
+	var err error
+	for _, endpoint := range c.endpoints.candidates() {
+		err = call(endpoint)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+		c.endpoints.markFailed(endpoint)
+		if !failover {
+			return err
+		}
+	}
+	return err
+}
+
+// txEndpoint returns the endpoint a tx is sent to, the healthiest one.
+func (c *l2Client) txEndpoint() string {
+	if c.endpoints == nil {
+		return c.endpoint
+	}
+	return c.endpoints.candidates()[0]
+}
+
+func (c *l2Client) getEndpointHeight(ctx context.Context, endpoint string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/api/v1/currentHeight", nil)
+	if err != nil {
+		return 0, err
+	}
+	result := &types.CurrentHeight{}
+	// health checks are not counted in the query budget of the caller
+	if err := c.do(req, nil, result); err != nil {
+		return 0, err
+	}
+	return result.Height, nil
+}
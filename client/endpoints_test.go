@@ -0,0 +1,112 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func heightServer(height *int64, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		h := atomic.LoadInt64(height)
+		if h < 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"code":100,"message":"","height":%d}`, h)
+	}))
+}
+
+func TestEndpointFailover(t *testing.T) {
+	var primaryHeight, fallbackHeight int64 = -1, 20
+	var primaryCalls, fallbackCalls int32
+	primary := heightServer(&primaryHeight, &primaryCalls)
+	defer primary.Close()
+	fallback := heightServer(&fallbackHeight, &fallbackCalls)
+	defer fallback.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(primary.URL, privateKey, chainNetworkId,
+		WithFallbackEndpoints(fallback.URL), WithHealthCheck(time.Hour, 10))
+	assert.NoError(t, err)
+
+	height, err := sdkClient.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), height)
+	assert.NotZero(t, atomic.LoadInt32(&primaryCalls))
+
+	// the failed endpoint is avoided until the next health check
+	client := sdkClient.(*l2Client)
+	assert.Equal(t, []string{fallback.URL, primary.URL}, client.endpoints.candidates())
+
+	// all endpoints down
+	atomic.StoreInt64(&fallbackHeight, -1)
+	_, err = sdkClient.GetCurrentHeight()
+	assert.ErrorIs(t, err, ErrServerError)
+}
+
+func TestEndpointHealthCheck(t *testing.T) {
+	var primaryHeight, fallbackHeight int64 = 100, 200
+	var primaryCalls, fallbackCalls int32
+	primary := heightServer(&primaryHeight, &primaryCalls)
+	defer primary.Close()
+	fallback := heightServer(&fallbackHeight, &fallbackCalls)
+	defer fallback.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(primary.URL, privateKey, chainNetworkId,
+		WithFallbackEndpoints(fallback.URL), WithHealthCheck(time.Hour, 10))
+	assert.NoError(t, err)
+	client := sdkClient.(*l2Client)
+
+	// the primary endpoint lags behind and is skipped
+	client.endpoints.refresh()
+	assert.Equal(t, []string{fallback.URL, primary.URL}, client.endpoints.candidates())
+
+	// once it caught up both endpoints are healthy again
+	atomic.StoreInt64(&primaryHeight, 195)
+	client.endpoints.refresh()
+	candidates := client.endpoints.candidates()
+	assert.ElementsMatch(t, []string{fallback.URL, primary.URL}, candidates)
+
+	atomic.StoreInt32(&primaryCalls, 0)
+	atomic.StoreInt32(&fallbackCalls, 0)
+	_, err = sdkClient.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&primaryCalls)+atomic.LoadInt32(&fallbackCalls))
+}
+
+func TestEndpointHealthCheckDisabled(t *testing.T) {
+	var primaryHeight, fallbackHeight int64 = 100, 100
+	var primaryCalls, fallbackCalls int32
+	primary := heightServer(&primaryHeight, &primaryCalls)
+	defer primary.Close()
+	fallback := heightServer(&fallbackHeight, &fallbackCalls)
+	defer fallback.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(primary.URL, privateKey, chainNetworkId,
+		WithFallbackEndpoints(fallback.URL), WithHealthCheck(0, 10))
+	assert.NoError(t, err)
+	client := sdkClient.(*l2Client)
+
+	// the endpoints are not checked, only the requests reach them
+	for i := 0; i < 3; i++ {
+		_, err = sdkClient.GetCurrentHeight()
+		assert.NoError(t, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&primaryCalls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fallbackCalls))
+
+	// failed endpoints are still avoided
+	client.endpoints.markFailed(primary.URL)
+	assert.Equal(t, []string{fallback.URL, primary.URL}, client.endpoints.candidates())
+
+	// a health check is bounded by the interval and by the request timeout
+	assert.Equal(t, time.Second, newEndpointPool(nil, time.Second, defaultRequestTimeout, 10).checkTimeout)
+	assert.Equal(t, defaultRequestTimeout, newEndpointPool(nil, time.Hour, defaultRequestTimeout, 10).checkTimeout)
+}
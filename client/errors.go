@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// APIError is returned when the ZkBNB api answers with a non 200 http status or with
+// a result code other than types.CodeOK.
+type APIError struct {
+	// StatusCode is the http status of the response
+	StatusCode int
+	// Code is the ZkBNB result code, it is 0 when the response carries no result
+	Code uint32
+	// Message is the result message, or the raw response body if it is not a result
+	Message string
+	// Path is the api path of the request, e.g. /api/v1/sendTx
+	Path string
+	// RetryAfter is the delay requested by the server through the Retry-After header
+	RetryAfter time.Duration
+}
+
+// ErrServerError matches every APIError with a 5xx http status
+var ErrServerError = errors.New("zkbnb server error")
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("zkbnb api %s: code %d: %s", e.Path, e.Code, e.Message)
+	}
+	return fmt.Sprintf("zkbnb api %s: http status %d: %s", e.Path, e.StatusCode, e.Message)
+}
+
+// Is reports whether the error has the same ZkBNB result code as target, or the same http
+// status when target has no code, so that errors.Is(err, &APIError{Code: code}) matches the
+// errors with that result code.
+func (e *APIError) Is(target error) bool {
+	if target == ErrServerError {
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	if t.Code != 0 {
+		return t.Code == e.Code
+	}
+	return t.StatusCode != 0 && t.StatusCode == e.StatusCode
+}
+
+func newAPIError(resp *http.Response, path string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+		Path:       path,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	result := &types.Result{}
+	if err := json.Unmarshal(body, result); err == nil && result.Code != 0 {
+		apiErr.Code = result.Code
+		apiErr.Message = result.Message
+	}
+	return apiErr
+}
+
+// isRejected reports whether ZkBNB answered the request with an error, as opposed to failing
+// to answer it with a transport error or a 5xx or 429 response.
+func isRejected(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && !IsRetryable(err)
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds
+// or a http date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
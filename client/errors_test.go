@@ -0,0 +1,49 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/account":
+			_, _ = w.Write([]byte(`{"code":21000,"message":"account not found"}`))
+		case "/api/v1/tx":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":24000,"message":"tx not found"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("bad gateway"))
+		}
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	_, err = sdkClient.GetAccountByIndex(1)
+	assert.ErrorIs(t, err, &APIError{Code: 21000})
+	assert.False(t, errors.Is(err, &APIError{Code: 24000}))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusOK, apiErr.StatusCode)
+	assert.Equal(t, "/api/v1/account", apiErr.Path)
+	assert.Equal(t, "account not found", apiErr.Message)
+
+	_, err = sdkClient.GetTx("0x01")
+	assert.ErrorIs(t, err, &APIError{Code: 24000})
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+	_, err = sdkClient.GetCurrentHeight()
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, uint32(0), apiErr.Code)
+	assert.Equal(t, "bad gateway", apiErr.Message)
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const defaultPageSize = 100
+
+// ZkBNBQueryIterator streams the items of the paginated queries. Pages are fetched lazily
+// in the background, one page ahead of the caller. A pageSize <= 0 uses pages of 100 items.
+type ZkBNBQueryIterator interface {
+	// IterateBlocks iterates over all blocks, see GetBlocks
+	IterateBlocks(ctx context.Context, pageSize int64) *Iterator[*types.Block]
+
+	// IterateTxs iterates over all txs, see GetTxs
+	IterateTxs(ctx context.Context, pageSize int64) *Iterator[*types.Tx]
+
+	// IterateTxsByAccountIndex iterates over the txs of an account, see GetTxsByAccountIndex
+	IterateTxsByAccountIndex(ctx context.Context, accountIndex int64, pageSize int64, options ...GetTxOptionFunc) *Iterator[*types.Tx]
+
+	// IterateAccounts iterates over all accounts, see GetAccounts
+	IterateAccounts(ctx context.Context, pageSize int64) *Iterator[*types.SimpleAccount]
+
+	// IterateAssets iterates over all assets, see GetAssets
+	IterateAssets(ctx context.Context, pageSize int64) *Iterator[*types.Asset]
+
+	// IterateNftsByAccountIndex iterates over the nfts of an account, see GetNftsByAccountIndex
+	IterateNftsByAccountIndex(ctx context.Context, accountIndex int64, pageSize int64) *Iterator[*types.Nft]
+
+	// IterateRollbacks iterates over the rollbacks from the given height, see GetRollbacks
+	IterateRollbacks(ctx context.Context, fromBlockHeight int64, pageSize int64) *Iterator[*types.Rollback]
+}
+
+// Iterator streams the items of an offset/limit query:
+//
+//	it := client.IterateTxs(ctx, 100)
+//	defer it.Close()
+//	for it.Next() {
+//		tx := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	pages  chan page[T]
+
+	items []T
+	item  T
+	err   error
+	done  bool
+}
+
+type page[T any] struct {
+	items []T
+	err   error
+}
+
+// fetchPage returns the total number of items and the items in [offset, offset+limit).
+type fetchPage[T any] func(ctx context.Context, offset, limit int64) (int64, []T, error)
+
+func newIterator[T any](ctx context.Context, pageSize int64, fetch fetchPage[T]) *Iterator[T] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	fetchCtx, cancel := context.WithCancel(ctx)
+	it := &Iterator[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		pages:  make(chan page[T], 1),
+	}
+	go it.prefetch(fetchCtx, pageSize, fetch)
+	return it
+}
+
+func (it *Iterator[T]) prefetch(ctx context.Context, pageSize int64, fetch fetchPage[T]) {
+	defer close(it.pages)
+	for offset := int64(0); ; offset += pageSize {
+		total, items, err := fetch(ctx, offset, pageSize)
+		select {
+		case it.pages <- page[T]{items: items, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil || int64(len(items)) < pageSize || offset+int64(len(items)) >= total {
+			return
+		}
+	}
+}
+
+// Next advances to the next item, it returns false once all items were read, an error
+// occurred or the context was cancelled.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	for len(it.items) == 0 {
+		p, ok := <-it.pages
+		if !ok {
+			it.finish(it.ctx.Err())
+			return false
+		}
+		if p.err != nil {
+			it.finish(p.err)
+			return false
+		}
+		if len(p.items) == 0 {
+			it.finish(nil)
+			return false
+		}
+		it.items = p.items
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.item
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close stops fetching pages. It must be called when the iteration is abandoned early.
+func (it *Iterator[T]) Close() {
+	it.cancel()
+	it.done = true
+	it.items = nil
+}
+
+func (it *Iterator[T]) finish(err error) {
+	it.err = err
+	it.done = true
+	it.cancel()
+}
+
+func (c *l2Client) IterateBlocks(ctx context.Context, pageSize int64) *Iterator[*types.Block] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Block, error) {
+		total, blocks, err := c.GetBlocksWithContext(ctx, offset, limit)
+		return int64(total), blocks, err
+	})
+}
+
+func (c *l2Client) IterateTxs(ctx context.Context, pageSize int64) *Iterator[*types.Tx] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Tx, error) {
+		total, txs, err := c.GetTxsWithContext(ctx, uint32(offset), uint32(limit))
+		return int64(total), txs, err
+	})
+}
+
+func (c *l2Client) IterateTxsByAccountIndex(ctx context.Context, accountIndex int64, pageSize int64, options ...GetTxOptionFunc) *Iterator[*types.Tx] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Tx, error) {
+		total, txs, err := c.GetTxsByAccountIndexWithContext(ctx, accountIndex, uint32(offset), uint32(limit), options...)
+		return int64(total), txs, err
+	})
+}
+
+func (c *l2Client) IterateAccounts(ctx context.Context, pageSize int64) *Iterator[*types.SimpleAccount] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.SimpleAccount, error) {
+		result, err := c.GetAccountsWithContext(ctx, uint32(offset), uint32(limit))
+		if err != nil {
+			return 0, nil, err
+		}
+		return int64(result.Total), result.Accounts, nil
+	})
+}
+
+func (c *l2Client) IterateAssets(ctx context.Context, pageSize int64) *Iterator[*types.Asset] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Asset, error) {
+		result, err := c.GetAssetsWithContext(ctx, uint32(offset), uint32(limit))
+		if err != nil {
+			return 0, nil, err
+		}
+		return int64(result.Total), result.Assets, nil
+	})
+}
+
+func (c *l2Client) IterateNftsByAccountIndex(ctx context.Context, accountIndex int64, pageSize int64) *Iterator[*types.Nft] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Nft, error) {
+		result, err := c.GetNftsByAccountIndexWithContext(ctx, accountIndex, offset, limit)
+		if err != nil {
+			return 0, nil, err
+		}
+		return result.Total, result.Nfts, nil
+	})
+}
+
+func (c *l2Client) IterateRollbacks(ctx context.Context, fromBlockHeight int64, pageSize int64) *Iterator[*types.Rollback] {
+	return newIterator(ctx, pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Rollback, error) {
+		total, rollbacks, err := c.GetRollbacksWithContext(ctx, fromBlockHeight, offset, limit)
+		return int64(total), rollbacks, err
+	})
+}
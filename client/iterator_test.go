@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func txsServer(total int, failAt int, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if failAt >= 0 && offset >= failAt {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result := &types.Txs{Total: uint32(total)}
+		for i := offset; i < offset+limit && i < total; i++ {
+			result.Txs = append(result.Txs, &types.Tx{Hash: strconv.Itoa(i)})
+		}
+		body, _ := json.Marshal(result)
+		_, _ = w.Write(append([]byte(`{"code":100,`), body[1:]...))
+	}))
+}
+
+func TestIterateTxs(t *testing.T) {
+	var requests int32
+	server := txsServer(250, -1, &requests)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	it := sdkClient.IterateTxs(context.Background(), 100)
+	defer it.Close()
+	var count int
+	for it.Next() {
+		assert.Equal(t, strconv.Itoa(count), it.Value().Hash)
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 250, count)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.False(t, it.Next())
+}
+
+func TestIterateTxsError(t *testing.T) {
+	var requests int32
+	server := txsServer(250, 100, &requests)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	it := sdkClient.IterateTxs(context.Background(), 100)
+	defer it.Close()
+	var count int
+	for it.Next() {
+		count++
+	}
+	assert.ErrorIs(t, it.Err(), ErrServerError)
+	assert.Equal(t, 100, count)
+}
+
+func TestIterateTxsCancel(t *testing.T) {
+	var requests int32
+	server := txsServer(1000, -1, &requests)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := sdkClient.IterateTxs(ctx, 10)
+	defer it.Close()
+	assert.True(t, it.Next())
+	cancel()
+	for it.Next() {
+	}
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.Less(t, atomic.LoadInt32(&requests), int32(100))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/bnb-chain/zkbnb-eth-rpc/core"
 	"github.com/bnb-chain/zkbnb-eth-rpc/rpc"
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
 	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
 
@@ -14,10 +15,20 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// L1Backend is the access to the L1 chain needed by the L1 client. It is implemented by
+// rpc.ProviderClient and can be backed by a simulated chain in tests.
+type L1Backend interface {
+	bind.ContractBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
 type L1Client struct {
+	// ProviderClient is nil when the client was created with NewZkBNBL1ClientWithBackend
 	*rpc.ProviderClient
 	ZkbnbContractInstance *core.ZkBNB
 	PrivateKey            *ecdsa.PrivateKey
+
+	backend L1Backend
 }
 
 func (c *L1Client) SetPrivateKey(pk string) error {
@@ -29,13 +40,25 @@ func (c *L1Client) SetPrivateKey(pk string) error {
 	return nil
 }
 
+func (c *L1Client) SetKeystore(path, passphrase string) error {
+	key, err := accounts.LoadL1Keystore(path, passphrase)
+	if err != nil {
+		return err
+	}
+	c.PrivateKey = key
+	return nil
+}
+
 func (c *L1Client) DepositBNB(l1Address string, amount *big.Int) (*types.Transaction, error) {
-	tx, err := c.DepositBNBWithTxReturn(l1Address, amount)
-	return tx, err
+	return c.DepositBNBWithContext(context.Background(), l1Address, amount)
 }
 
 func (c *L1Client) DepositBNBWithTxReturn(l1Address string, amount *big.Int) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
+	return c.DepositBNBWithContext(context.Background(), l1Address, amount)
+}
+
+func (c *L1Client) DepositBNBWithContext(ctx context.Context, l1Address string, amount *big.Int) (*types.Transaction, error) {
+	opts, err := c.getTransactor(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +72,11 @@ func (c *L1Client) DepositBNBWithTxReturn(l1Address string, amount *big.Int) (*t
 }
 
 func (c *L1Client) DepositBEP20(token common.Address, l1Address string, amount *big.Int) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
+	return c.DepositBEP20WithContext(context.Background(), token, l1Address, amount)
+}
+
+func (c *L1Client) DepositBEP20WithContext(ctx context.Context, token common.Address, l1Address string, amount *big.Int) (*types.Transaction, error) {
+	opts, err := c.getTransactor(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -62,20 +89,15 @@ func (c *L1Client) DepositBEP20(token common.Address, l1Address string, amount *
 }
 
 func (c *L1Client) DepositBEP20WithTxReturn(token common.Address, l1Address string, amount *big.Int) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
-	if err != nil {
-		return nil, err
-	}
-
-	tx, err := c.ZkbnbContractInstance.DepositBEP20(opts, token, amount, common.HexToAddress(l1Address))
-	if err != nil {
-		return nil, err
-	}
-	return tx, nil
+	return c.DepositBEP20WithContext(context.Background(), token, l1Address, amount)
 }
 
 func (c *L1Client) DepositNft(nftL1Address common.Address, l1Address string, nftL1TokenId *big.Int) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
+	return c.DepositNftWithContext(context.Background(), nftL1Address, l1Address, nftL1TokenId)
+}
+
+func (c *L1Client) DepositNftWithContext(ctx context.Context, nftL1Address common.Address, l1Address string, nftL1TokenId *big.Int) (*types.Transaction, error) {
+	opts, err := c.getTransactor(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +110,11 @@ func (c *L1Client) DepositNft(nftL1Address common.Address, l1Address string, nft
 }
 
 func (c *L1Client) RequestFullExit(accountIndex uint32, asset common.Address) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
+	return c.RequestFullExitWithContext(context.Background(), accountIndex, asset)
+}
+
+func (c *L1Client) RequestFullExitWithContext(ctx context.Context, accountIndex uint32, asset common.Address) (*types.Transaction, error) {
+	opts, err := c.getTransactor(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +127,11 @@ func (c *L1Client) RequestFullExit(accountIndex uint32, asset common.Address) (*
 }
 
 func (c *L1Client) RequestFullExitNft(accountIndex uint32, nftIndex uint32) (*types.Transaction, error) {
-	opts, err := c.getTransactor(nil)
+	return c.RequestFullExitNftWithContext(context.Background(), accountIndex, nftIndex)
+}
+
+func (c *L1Client) RequestFullExitNftWithContext(ctx context.Context, accountIndex uint32, nftIndex uint32) (*types.Transaction, error) {
+	opts, err := c.getTransactor(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -112,16 +142,16 @@ func (c *L1Client) RequestFullExitNft(accountIndex uint32, nftIndex uint32) (*ty
 	return tx, nil
 }
 
-func (c *L1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
+func (c *L1Client) getTransactor(ctx context.Context, value *big.Int) (*bind.TransactOpts, error) {
 	if c.PrivateKey == nil {
 		return nil, fmt.Errorf("private key is not set")
 	}
 
-	nonce, err := c.PendingNonceAt(context.Background(), getAddressFromPrivateKey(c.PrivateKey))
+	nonce, err := c.backend.PendingNonceAt(ctx, getAddressFromPrivateKey(c.PrivateKey))
 	if err != nil {
 		return nil, err
 	}
-	chainId, err := c.ChainID(context.Background())
+	chainId, err := c.backend.ChainID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +161,7 @@ func (c *L1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
 		return nil, err
 	}
 
-	gasPrice, err := c.SuggestGasPrice(context.Background())
+	gasPrice, err := c.backend.SuggestGasPrice(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +169,7 @@ func (c *L1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = value // in wei
 	auth.GasPrice = gasPrice
+	auth.Context = ctx
 	return auth, nil
 }
 
@@ -1,12 +1,11 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"io"
 	"math/big"
 	"net"
@@ -14,35 +13,33 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
 	"github.com/bnb-chain/zkbnb-go-sdk/signer"
 	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
 	"github.com/bnb-chain/zkbnb-go-sdk/types"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 )
 
 const defaultExpireTime = time.Minute * 10
 
-var (
-	dialer = &net.Dialer{
-		Timeout:   1 * time.Second,
-		KeepAlive: 60 * time.Second,
-	}
-	transport = &http.Transport{
-		DialContext:         dialer.DialContext,
+// HttpClient is the http client of the clients created without transport option, see
+// WithHttpClient, WithTLSConfig, WithProxy, WithTimeout and WithDialTimeout.
+//
+// Deprecated: configure the transport per client with the options instead.
+var HttpClient = &http.Client{
+	Timeout: defaultRequestTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: 60 * time.Second,
+		}).DialContext,
 		MaxConnsPerHost:     1000,
 		MaxIdleConnsPerHost: 100,
 		IdleConnTimeout:     10 * time.Second,
-		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
-	}
-
-	HttpClient = &http.Client{
-		Timeout:   time.Second * 10,
-		Transport: transport,
-	}
-)
+	},
+}
 
 type l2Client struct {
 	endpoint    string
@@ -52,36 +49,49 @@ type l2Client struct {
 	channelName string
 	l1Signer    signer.L1Signer
 	keyManager  accounts.KeyManager
+	httpClient  *http.Client
+	userAgent   string
+	retryPolicy RetryPolicy
+
+	queryLimiter  *Limiter
+	sendTxLimiter *Limiter
+	limiterHook   func(path string, waited time.Duration)
+
+	endpoints    *endpointPool
+	nonceManager *NonceManager
+	cache        *clientCache
+
+	typedDataSigning bool
+	zkbnbContract    string
+	signModeMu       sync.Mutex
+	signMode         *l1SignMode
 }
 
 func (c *l2Client) KeyManager() accounts.KeyManager {
 	return c.keyManager
 }
 
+func (c *l2Client) NonceManager() *NonceManager {
+	return c.nonceManager
+}
+
 func (c *l2Client) GetCurrentHeight() (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint + fmt.Sprintf("/api/v1/currentHeight"))
-	if err != nil {
-		return -1, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return -1, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return -1, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return -1, err
-	}
+	return c.GetCurrentHeightWithContext(context.Background())
+}
+
+func (c *l2Client) GetCurrentHeightWithContext(ctx context.Context) (int64, error) {
 	result := &types.CurrentHeight{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "/api/v1/currentHeight", result); err != nil {
 		return -1, err
 	}
 	return result.Height, nil
 }
 
 func (c *l2Client) GetTxsByL1Address(l1Address string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetTxsByL1AddressWithContext(context.Background(), l1Address, offset, limit, options...)
+}
+
+func (c *l2Client) GetTxsByL1AddressWithContext(ctx context.Context, l1Address string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
@@ -93,53 +103,30 @@ func (c *l2Client) GetTxsByL1Address(l1Address string, offset, limit uint32, opt
 		path += fmt.Sprintf("&types=%s", string(txTypes))
 	}
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, path, result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) GetTxs(offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/txs?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
+	return c.GetTxsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/txs?offset=%d&limit=%d", offset, limit), result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) GetTxsByAccountIndex(accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetTxsByAccountIndexWithContext(context.Background(), accountIndex, offset, limit, options...)
+}
+
+func (c *l2Client) GetTxsByAccountIndexWithContext(ctx context.Context, accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
@@ -151,216 +138,92 @@ func (c *l2Client) GetTxsByAccountIndex(accountIndex int64, offset, limit uint32
 		path += fmt.Sprintf("&types=%s", string(txTypes))
 	}
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, path, result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) Search(keyword string) (*types.Search, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/search?keyword=%s", keyword))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.SearchWithContext(context.Background(), keyword)
+}
+
+func (c *l2Client) SearchWithContext(ctx context.Context, keyword string) (*types.Search, error) {
 	result := &types.Search{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/search?keyword=%s", keyword), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAccounts(offset, limit uint32) (*types.Accounts, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/accounts?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetAccountsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetAccountsWithContext(ctx context.Context, offset, limit uint32) (*types.Accounts, error) {
 	result := &types.Accounts{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/accounts?offset=%d&limit=%d", offset, limit), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetGasFeeAssets() (*types.GasFeeAssets, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/gasFeeAssets")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetGasFeeAssetsWithContext(context.Background())
+}
+
+func (c *l2Client) GetGasFeeAssetsWithContext(ctx context.Context) (*types.GasFeeAssets, error) {
 	result := &types.GasFeeAssets{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "/api/v1/gasFeeAssets", result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetGasFee(assetId int64, txType int) (*big.Int, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/gasFee?asset_id=%d&tx_type=%d", assetId, txType))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
-	result := &types.GasFee{}
-	if err := json.Unmarshal(body, result); err != nil {
-		return nil, err
-	}
-	var price big.Int
-	price.SetString(result.GasFee, 10)
-	return &price, nil
+	return c.GetGasFeeWithContext(context.Background(), assetId, txType)
+}
+
+func (c *l2Client) GetGasFeeWithContext(ctx context.Context, assetId int64, txType int) (*big.Int, error) {
+	return c.fetchGasFee(ctx, assetId, txType)
 }
 
 func (c *l2Client) GetAssetById(id uint32) (*types.Asset, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/asset?by=id&value=%d", id))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
-	result := &types.Asset{}
-	if err := json.Unmarshal(body, result); err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.GetAssetByIdWithContext(context.Background(), id)
+}
+
+func (c *l2Client) GetAssetByIdWithContext(ctx context.Context, id uint32) (*types.Asset, error) {
+	return c.fetchAsset(ctx, "id", strconv.FormatUint(uint64(id), 10))
 }
 
 func (c *l2Client) GetAssetBySymbol(symbol string) (*types.Asset, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/asset?by=symbol&value=%s", symbol))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
-	result := &types.Asset{}
-	if err := json.Unmarshal(body, result); err != nil {
-		return nil, err
-	}
-	return result, nil
+	return c.GetAssetBySymbolWithContext(context.Background(), symbol)
+}
+
+func (c *l2Client) GetAssetBySymbolWithContext(ctx context.Context, symbol string) (*types.Asset, error) {
+	return c.fetchAsset(ctx, "symbol", symbol)
 }
 
 func (c *l2Client) GetAssets(offset, limit uint32) (*types.Assets, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/assets?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetAssetsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetAssetsWithContext(ctx context.Context, offset, limit uint32) (*types.Assets, error) {
 	result := &types.Assets{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/assets?offset=%d&limit=%d", offset, limit), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetProtocolRate() (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/getProtocolRate"))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, err
-	}
+	return c.GetProtocolRateWithContext(context.Background())
+}
+
+func (c *l2Client) GetProtocolRateWithContext(ctx context.Context) (int64, error) {
 	result := &types.ProtocolRate{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "/api/v1/getProtocolRate", result); err != nil {
 		return 0, err
 	}
 	platformFeeRate, err := strconv.ParseInt(result.ProtocolRate, 10, 64)
@@ -371,149 +234,82 @@ func (c *l2Client) GetProtocolRate() (int64, error) {
 }
 
 func (c *l2Client) GetLayer2BasicInfo() (*types.Layer2BasicInfo, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/layer2BasicInfo")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetLayer2BasicInfoWithContext(context.Background())
+}
+
+func (c *l2Client) GetLayer2BasicInfoWithContext(ctx context.Context) (*types.Layer2BasicInfo, error) {
 	result := &types.Layer2BasicInfo{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "/api/v1/layer2BasicInfo", result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetRollbacks(fromBlockHeight, offset, limit int64) (total uint32, rollbacks []*types.Rollback, err error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/rollbacks?from_block_height=%d&limit=%d&offset=%d", fromBlockHeight, limit, offset))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
+	return c.GetRollbacksWithContext(context.Background(), fromBlockHeight, offset, limit)
+}
+
+func (c *l2Client) GetRollbacksWithContext(ctx context.Context, fromBlockHeight, offset, limit int64) (total uint32, rollbacks []*types.Rollback, err error) {
 	result := &types.Rollbacks{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/rollbacks?from_block_height=%d&limit=%d&offset=%d", fromBlockHeight, limit, offset), result); err != nil {
 		return 0, nil, err
 	}
-	return result.Total, result.Rollbacks, err
+	return result.Total, result.Rollbacks, nil
 }
 
 func (c *l2Client) GetBlockByCommitment(blockCommitment string) (*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/block?by=commitment&value=%s", blockCommitment))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetBlockByCommitmentWithContext(context.Background(), blockCommitment)
+}
+
+func (c *l2Client) GetBlockByCommitmentWithContext(ctx context.Context, blockCommitment string) (*types.Block, error) {
 	result := &types.Block{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/block?by=commitment&value=%s", blockCommitment), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAccountByIndex(accountIndex int64) (*types.Account, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/account?by=index&value=%d", accountIndex))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetAccountByIndexWithContext(context.Background(), accountIndex)
+}
+
+func (c *l2Client) GetAccountByIndexWithContext(ctx context.Context, accountIndex int64) (*types.Account, error) {
 	result := &types.Account{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/account?by=index&value=%d", accountIndex), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetTx(hash string) (*types.EnrichedTx, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/tx?hash=%s", hash))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetTxWithContext(context.Background(), hash)
+}
+
+func (c *l2Client) GetTxWithContext(ctx context.Context, hash string) (*types.EnrichedTx, error) {
 	txResp := &types.EnrichedTx{}
-	if err := json.Unmarshal(body, txResp); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/tx?hash=%s", hash), txResp); err != nil {
 		return nil, err
 	}
 	return txResp, nil
 }
 
 func (c *l2Client) GetPendingTxs(offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/pendingTxs?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
+	return c.GetPendingTxsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetPendingTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/pendingTxs?offset=%d&limit=%d", offset, limit), txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
 func (c *l2Client) GetPendingTxsByL1Address(l1Address string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetPendingTxsByL1AddressWithContext(context.Background(), l1Address, options...)
+}
+
+func (c *l2Client) GetPendingTxsByL1AddressWithContext(ctx context.Context, l1Address string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
@@ -525,29 +321,18 @@ func (c *l2Client) GetPendingTxsByL1Address(l1Address string, options ...GetTxOp
 		path += fmt.Sprintf("&types=%s", string(txTypes))
 	}
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, path, txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
 func (c *l2Client) GetExecutedTxs(offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetExecutedTxsWithContext(context.Background(), offset, limit, options...)
+}
+
+func (c *l2Client) GetExecutedTxsWithContext(ctx context.Context, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
@@ -558,321 +343,161 @@ func (c *l2Client) GetExecutedTxs(offset, limit uint32, options ...GetTxOptionFu
 		path += fmt.Sprintf("&from_hash=%s", opt.FromHash)
 	}
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, path, txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
-func (c *l2Client) GetAccountByL1Address(l1Address string) (*types.Account, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/account?by=l1_address&value=" + l1Address)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
-	account := &types.Account{}
-	if err := json.Unmarshal(body, account); err != nil {
-		return nil, err
-	}
-	return account, nil
-}
-
-func (c *l2Client) GetNextNonce(accountIdx int64) (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/nextNonce?account_index=%d", accountIdx))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, err
+func (c *l2Client) GetAccountByL1Address(l1Address string) (*types.Account, error) {
+	return c.GetAccountByL1AddressWithContext(context.Background(), l1Address)
+}
+
+func (c *l2Client) GetAccountByL1AddressWithContext(ctx context.Context, l1Address string) (*types.Account, error) {
+	account := &types.Account{}
+	if err := c.get(ctx, "/api/v1/account?by=l1_address&value="+l1Address, account); err != nil {
+		return nil, err
 	}
+	return account, nil
+}
+
+func (c *l2Client) GetNextNonce(accountIdx int64) (int64, error) {
+	return c.GetNextNonceWithContext(context.Background(), accountIdx)
+}
+
+func (c *l2Client) GetNextNonceWithContext(ctx context.Context, accountIdx int64) (int64, error) {
 	result := &types.NextNonce{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/nextNonce?account_index=%d", accountIdx), result); err != nil {
 		return 0, err
 	}
 	return int64(result.Nonce), nil
 }
 
 func (c *l2Client) GetTxsByBlockHeight(blockHeight uint32) ([]*types.Tx, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/blockTxs?by=block_height&value=%d", blockHeight))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetTxsByBlockHeightWithContext(context.Background(), blockHeight)
+}
+
+func (c *l2Client) GetTxsByBlockHeightWithContext(ctx context.Context, blockHeight uint32) ([]*types.Tx, error) {
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/blockTxs?by=block_height&value=%d", blockHeight), result); err != nil {
 		return nil, err
 	}
 	return result.Txs, nil
 }
 
 func (c *l2Client) GetMaxOfferId(accountIndex int64) (uint64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/maxOfferId?account_index=%d", accountIndex))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, err
-	}
+	return c.GetMaxOfferIdWithContext(context.Background(), accountIndex)
+}
+
+func (c *l2Client) GetMaxOfferIdWithContext(ctx context.Context, accountIndex int64) (uint64, error) {
 	result := &types.MaxOfferId{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/maxOfferId?account_index=%d", accountIndex), result); err != nil {
 		return 0, err
 	}
 	return result.OfferId, nil
 }
 
 func (c *l2Client) GetBlockByHeight(blockHeight int64) (*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/block?by=height&value=%d", blockHeight))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetBlockByHeightWithContext(context.Background(), blockHeight)
+}
+
+func (c *l2Client) GetBlockByHeightWithContext(ctx context.Context, blockHeight int64) (*types.Block, error) {
 	res := &types.Block{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/block?by=height&value=%d", blockHeight), res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) GetBlocks(offset, limit int64) (uint32, []*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/blocks?limit=%d&offset=%d", limit, offset))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, nil, err
-	}
+	return c.GetBlocksWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetBlocksWithContext(ctx context.Context, offset, limit int64) (uint32, []*types.Block, error) {
 	res := &types.Blocks{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/blocks?limit=%d&offset=%d", limit, offset), res); err != nil {
 		return 0, nil, err
 	}
 	return res.Total, res.Blocks, nil
 }
 
 func (c *l2Client) GetGasAccount() (*types.GasAccount, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/gasAccount"))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
-	res := &types.GasAccount{}
-	if err := json.Unmarshal(body, res); err != nil {
-		return nil, err
-	}
-	return res, nil
+	return c.GetGasAccountWithContext(context.Background())
+}
+
+func (c *l2Client) GetGasAccountWithContext(ctx context.Context) (*types.GasAccount, error) {
+	return c.fetchGasAccount(ctx)
 }
 
 func (c *l2Client) GetNftsByAccountIndex(accountIndex, offset, limit int64) (*types.Nfts, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/accountNfts?by=account_index&value=%d&limit=%d&offset=%d", accountIndex, limit, offset))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetNftsByAccountIndexWithContext(context.Background(), accountIndex, offset, limit)
+}
+
+func (c *l2Client) GetNftsByAccountIndexWithContext(ctx context.Context, accountIndex, offset, limit int64) (*types.Nfts, error) {
 	res := &types.Nfts{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/accountNfts?by=account_index&value=%d&limit=%d&offset=%d", accountIndex, limit, offset), res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) GetNftByNftIndex(nftIndex int64) (*types.Nft, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/GetNftByNftIndex?nft_index=%d", nftIndex))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetNftByNftIndexWithContext(context.Background(), nftIndex)
+}
+
+func (c *l2Client) GetNftByNftIndexWithContext(ctx context.Context, nftIndex int64) (*types.Nft, error) {
 	res := &types.NftEntity{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/GetNftByNftIndex?nft_index=%d", nftIndex), res); err != nil {
 		return nil, err
 	}
 	return res.Nft, nil
 }
 
-func (c *l2Client) getL2SignatureBody(txType uint32, txInfo string) (string, error) {
-	resp, err := HttpClient.PostForm(c.endpoint+"/api/v1/l2Signature",
-		url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}, "tx_signature": {"-"}})
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return "", err
-	}
+func (c *l2Client) getL2SignatureBody(ctx context.Context, txType uint32, txInfo string) (string, error) {
 	res := &types.SignBody{}
-	if err := json.Unmarshal(body, res); err != nil {
+	err := c.postForm(ctx, "/api/v1/l2Signature",
+		url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}, "tx_signature": {"-"}}, res)
+	if err != nil {
 		return "", err
 	}
 	return res.SignBody, nil
 }
 
 func (c *l2Client) GetMaxCollectionId(accountIndex int64) (*types.MaxCollectionId, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/maxCollectionId?account_index=%d", accountIndex))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetMaxCollectionIdWithContext(context.Background(), accountIndex)
+}
+
+func (c *l2Client) GetMaxCollectionIdWithContext(ctx context.Context, accountIndex int64) (*types.MaxCollectionId, error) {
 	result := &types.MaxCollectionId{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/maxCollectionId?account_index=%d", accountIndex), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetNftByTxHash(txHash string) (*types.NftIndex, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/getNftByTxHash?tx_hash=%s", txHash))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	return c.GetNftByTxHashWithContext(context.Background(), txHash)
+}
+
+func (c *l2Client) GetNftByTxHashWithContext(ctx context.Context, txHash string) (*types.NftIndex, error) {
 	result := &types.NftIndex{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/getNftByTxHash?tx_hash=%s", txHash), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) UpdateNftByIndex(nft *types.UpdateNftReq, signatureList ...string) (*types.Mutable, error) {
-	txInfo, err := c.constructUpdateNFTTransaction(nft, nil)
+	return c.UpdateNftByIndexWithContext(context.Background(), nft, signatureList...)
+}
+
+func (c *l2Client) UpdateNftByIndexWithContext(ctx context.Context, nft *types.UpdateNftReq, signatureList ...string) (*types.Mutable, error) {
+	txInfo, err := c.constructUpdateNFTTransaction(ctx, nft, nil)
 	if err != nil {
 		return nil, err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return nil, err
 	}
@@ -881,81 +506,64 @@ func (c *l2Client) UpdateNftByIndex(nft *types.UpdateNftReq, signatureList ...st
 	if err != nil {
 		return nil, err
 	}
-	resp, err := HttpClient.PostForm(c.endpoint+"/api/v1/updateNftByIndex",
-		url.Values{"tx_info": {string(txInfoBytes)}})
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return nil, err
-	}
+	form := url.Values{"tx_info": {string(txInfoBytes)}}
+	signModeForm(WithL1SignMode(ctx, signMode), form)
 	res := &types.Mutable{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.postForm(ctx, "/api/v1/updateNftByIndex", form, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) GetNftNextNonce(nftIndex int64) (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/nftNextNonce?nft_index=%d", nftIndex))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return 0, err
-	}
+	return c.getNftNextNonce(context.Background(), nftIndex)
+}
+
+func (c *l2Client) getNftNextNonce(ctx context.Context, nftIndex int64) (int64, error) {
 	result := &types.NextNonce{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/nftNextNonce?nft_index=%d", nftIndex), result); err != nil {
 		return 0, err
 	}
 	return int64(result.Nonce), nil
 }
 
 func (c *l2Client) SendRawTx(txType uint32, txInfo string) (string, error) {
-	data := url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}
-	req, _ := http.NewRequest("POST", c.endpoint+"/api/v1/sendTx", strings.NewReader(data.Encode()))
-	req.Header.Set("Channel-Name", c.channelName)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := HttpClient.Do(req)
-	if err != nil {
-		return "", err
+	return c.SendRawTxWithContext(context.Background(), txType, txInfo)
+}
+
+func (c *l2Client) SendRawTxWithContext(ctx context.Context, txType uint32, txInfo string) (string, error) {
+	txHash, err := c.sendRawTxWithRetry(ctx, txType, txInfo)
+	if c.nonceManager != nil {
+		c.nonceManager.settle(txType, txInfo, err)
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return txHash, err
+}
+
+// sendRawTx sends the tx to the given endpoint, see sendRawTxWithRetry.
+func (c *l2Client) sendRawTx(ctx context.Context, endpoint string, txType uint32, txInfo string) (string, error) {
+	data := url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}
+	signModeForm(ctx, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+sendTxPath, strings.NewReader(data.Encode()))
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf(string(body))
-	}
-	if err = c.parseResultStatus(body); err != nil {
-		return "", err
-	}
+	req.Header.Set("Channel-Name", c.channelName)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	res := &types.TxHash{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.do(req, c.sendTxLimiter, res); err != nil {
+		if c.endpoints != nil && IsRetryable(err) {
+			c.endpoints.markFailed(endpoint)
+		}
 		return "", err
 	}
 	return res.TxHash, nil
 }
 
 func (c *l2Client) ChangePubKey(tx *types.ChangePubKeyReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.ChangePubKeyWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) ChangePubKeyWithContext(ctx context.Context, tx *types.ChangePubKeyReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -963,11 +571,13 @@ func (c *l2Client) ChangePubKey(tx *types.ChangePubKeyReq, ops *types.TransactOp
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
-	txInfo, err := c.constructChangePubKeyTransaction(tx, ops)
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
+	txInfo, err := c.constructChangePubKeyTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -976,10 +586,14 @@ func (c *l2Client) ChangePubKey(tx *types.ChangePubKeyReq, ops *types.TransactOp
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) MintNft(tx *types.MintNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.MintNftWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) MintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -987,13 +601,15 @@ func (c *l2Client) MintNft(tx *types.MintNftTxReq, ops *types.TransactOpts, sign
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructMintNftTransaction(tx, ops)
+	txInfo, err := c.constructMintNftTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1002,10 +618,14 @@ func (c *l2Client) MintNft(tx *types.MintNftTxReq, ops *types.TransactOpts, sign
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) CreateCollection(tx *types.CreateCollectionTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.CreateCollectionWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) CreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1013,12 +633,14 @@ func (c *l2Client) CreateCollection(tx *types.CreateCollectionTxReq, ops *types.
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructCreateCollectionTransaction(tx, ops)
+	txInfo, err := c.constructCreateCollectionTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1027,10 +649,14 @@ func (c *l2Client) CreateCollection(tx *types.CreateCollectionTxReq, ops *types.
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) CancelOffer(tx *types.CancelOfferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.CancelOfferWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) CancelOfferWithContext(ctx context.Context, tx *types.CancelOfferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1038,13 +664,15 @@ func (c *l2Client) CancelOffer(tx *types.CancelOfferTxReq, ops *types.TransactOp
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructCancelOfferTransaction(tx, ops)
+	txInfo, err := c.constructCancelOfferTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1053,17 +681,23 @@ func (c *l2Client) CancelOffer(tx *types.CancelOfferTxReq, ops *types.TransactOp
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) AtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (string, error) {
+	return c.AtomicMatchWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) AtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
-	txInfo, err := c.constructAtomicMatchTransaction(tx, ops)
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
+	txInfo, err := c.constructAtomicMatchTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
@@ -1071,10 +705,14 @@ func (c *l2Client) AtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOp
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(ctx, nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) WithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.WithdrawNftWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) WithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1082,12 +720,14 @@ func (c *l2Client) WithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOp
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructWithdrawNftTransaction(tx, ops)
+	txInfo, err := c.constructWithdrawNftTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1097,10 +737,14 @@ func (c *l2Client) WithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOp
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) TransferNft(tx *types.TransferNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.TransferNftWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) TransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1108,12 +752,14 @@ func (c *l2Client) TransferNft(tx *types.TransferNftTxReq, ops *types.TransactOp
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructTransferNftTransaction(tx, ops)
+	txInfo, err := c.constructTransferNftTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1122,10 +768,14 @@ func (c *l2Client) TransferNft(tx *types.TransferNftTxReq, ops *types.TransactOp
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) Withdraw(tx *types.WithdrawTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.WithdrawWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) WithdrawWithContext(ctx context.Context, tx *types.WithdrawTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1133,12 +783,14 @@ func (c *l2Client) Withdraw(tx *types.WithdrawTxReq, ops *types.TransactOpts, si
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructWithdrawTransaction(tx, ops)
+	txInfo, err := c.constructWithdrawTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1148,10 +800,14 @@ func (c *l2Client) Withdraw(tx *types.WithdrawTxReq, ops *types.TransactOpts, si
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
 func (c *l2Client) Transfer(tx *types.TransferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
+	return c.TransferWithContext(context.Background(), tx, ops, signatureList...)
+}
+
+func (c *l2Client) TransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts, signatureList ...string) (string, error) {
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -1159,12 +815,14 @@ func (c *l2Client) Transfer(tx *types.TransferTxReq, ops *types.TransactOpts, si
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
+	ctx, nonce := c.withTxNonce(ctx)
+	defer nonce.release()
 
-	txInfo, err := c.constructTransferTransaction(tx, ops)
+	txInfo, err := c.constructTransferTransaction(ctx, tx, ops)
 	if err != nil {
 		return "", err
 	}
-	signature, err := c.generateSignature(txInfo, signatureList)
+	signature, signMode, err := c.generateSignature(ctx, txInfo, signatureList)
 	if err != nil {
 		return "", err
 	}
@@ -1173,11 +831,11 @@ func (c *l2Client) Transfer(tx *types.TransferTxReq, ops *types.TransactOpts, si
 	if err != nil {
 		return "", err
 	}
-	return c.SendRawTx(uint32(txInfo.GetTxType()), string(txInfoBytes))
+	return c.sendTx(WithL1SignMode(ctx, signMode), nonce, uint32(txInfo.GetTxType()), string(txInfoBytes))
 }
 
-func (c *l2Client) fullFillToAddrOps(ops *types.TransactOpts, to string) (*types.TransactOpts, error) {
-	toAccount, err := c.GetAccountByL1Address(to)
+func (c *l2Client) fullFillToAddrOps(ctx context.Context, ops *types.TransactOpts, to string) (*types.TransactOpts, error) {
+	toAccount, err := c.getAccountIndexByL1Address(ctx, to)
 	if err != nil {
 		return nil, err
 	}
@@ -1186,12 +844,12 @@ func (c *l2Client) fullFillToAddrOps(ops *types.TransactOpts, to string) (*types
 	return ops, nil
 }
 
-func (c *l2Client) fullFillDefaultOps(ops *types.TransactOpts) (*types.TransactOpts, error) {
+func (c *l2Client) fullFillDefaultOps(ctx context.Context, ops *types.TransactOpts) (*types.TransactOpts, error) {
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
 	if ops.GasAccountIndex == 0 {
-		gasAccount, err := c.GetGasAccount()
+		gasAccount, err := c.GetGasAccountWithContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -1204,29 +862,30 @@ func (c *l2Client) fullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 		ops.ExpiredAt = time.Now().Add(defaultExpireTime).UnixMilli()
 	}
 	if ops.FromAccountIndex == 0 {
-		l2Account, err := c.GetAccountByL1Address(c.address)
+		l2Account, err := c.getAccountIndexByL1Address(ctx, c.address)
 		if err != nil {
 			return nil, err
 		}
 		ops.FromAccountIndex = l2Account.Index
 	}
 	if ops.Nonce == 0 {
-		nonce, err := c.GetNextNonce(ops.FromAccountIndex)
+		var nonce int64
+		var err error
+		if txNonce, ok := ctx.Value(txNonceKey{}).(*txNonce); ok && c.nonceManager != nil {
+			nonce, err = txNonce.reserve(ctx, ops.FromAccountIndex)
+		} else {
+			nonce, err = c.GetNextNonceWithContext(ctx, ops.FromAccountIndex)
+		}
 		if err != nil {
 			return nil, err
 		}
 		ops.Nonce = nonce
 	}
 	if len(ops.CallDataHash) == 0 {
-		hFunc := mimc.NewMiMC()
-		var x fr.Element
-		_ = x.SetBytes([]byte(ops.CallData))
-		b := x.Bytes()
-		hFunc.Write(b[:])
-		ops.CallDataHash = hFunc.Sum(nil)
+		ops.CallDataHash = txutils.ComputeCallDataHash(ops.CallData)
 	}
 	if ops.GasFeeAssetAmount == nil {
-		gas, err := c.GetGasFee(ops.GasFeeAssetId, ops.TxType)
+		gas, err := c.GetGasFeeWithContext(ctx, ops.GasFeeAssetId, ops.TxType)
 		if err != nil {
 			return nil, err
 		}
@@ -1235,27 +894,32 @@ func (c *l2Client) fullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 	return ops, nil
 }
 
-func (c *l2Client) generateSignature(txInfo txtypes.TxInfo, signatureList []string) (string, error) {
+// generateSignature returns the L1 signature of the tx and the sign mode it was made with. A
+// passed signature is taken to be made with the sign mode of the context, see WithL1SignMode.
+func (c *l2Client) generateSignature(ctx context.Context, txInfo txtypes.TxInfo, signatureList []string) (string, signer.L1SignMode, error) {
 	if len(signatureList) == 0 {
 		if c.l1Signer == nil {
-			return "", errors.New("PrivateKey has not been initialized correctly, signature is expected to be passed instead")
+			return "", "", errors.New("PrivateKey has not been initialized correctly, signature is expected to be passed instead")
 		}
 
-		signBody := txInfo.GetL1SignatureBody()
-		signHex, err := c.l1Signer.Sign(signBody)
+		signHex, signMode, err := c.signL1(ctx, txInfo)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
-		return signHex, nil
+		return signHex, signMode, nil
 	} else if len(signatureList) == 1 {
-		return signatureList[0], nil
+		return signatureList[0], l1SignModeFromContext(ctx), nil
 	} else {
-		return "", errors.New("the passed signatureList contains more than one signature value and it is illegal")
+		return "", "", errors.New("the passed signatureList contains more than one signature value and it is illegal")
 	}
 }
 
 func (c *l2Client) GenerateSignBody(txData interface{}, ops *types.TransactOpts) (string, error) {
-	txInfo, err := c.constructTransaction(txData, ops)
+	return c.GenerateSignBodyWithContext(context.Background(), txData, ops)
+}
+
+func (c *l2Client) GenerateSignBodyWithContext(ctx context.Context, txData interface{}, ops *types.TransactOpts) (string, error) {
+	txInfo, err := c.constructTransaction(ctx, txData, ops)
 	if err != nil {
 		return "", err
 	}
@@ -1264,18 +928,19 @@ func (c *l2Client) GenerateSignBody(txData interface{}, ops *types.TransactOpts)
 }
 
 func (c *l2Client) GenerateSignature(privateKey string, txData interface{}, ops *types.TransactOpts) (string, error) {
+	return c.GenerateSignatureWithContext(context.Background(), privateKey, txData, ops)
+}
+
+func (c *l2Client) GenerateSignatureWithContext(ctx context.Context, privateKey string, txData interface{}, ops *types.TransactOpts) (string, error) {
 	l1Signer, err := signer.NewL1Singer(privateKey)
 	if err != nil {
 		return "", err
 	}
-	txInfo, err := c.constructTransaction(txData, ops)
+	txInfo, err := c.constructTransaction(ctx, txData, ops)
 	if err != nil {
 		return "", err
 	}
 	signBody := txInfo.GetL1SignatureBody()
-	if err != nil {
-		return "", err
-	}
 	signHex, err := l1Signer.Sign(signBody)
 	if err != nil {
 		return "", err
@@ -1283,37 +948,37 @@ func (c *l2Client) GenerateSignature(privateKey string, txData interface{}, ops
 	return signHex, nil
 }
 
-func (c *l2Client) constructTransaction(tx interface{}, ops *types.TransactOpts) (txtypes.TxInfo, error) {
+func (c *l2Client) constructTransaction(ctx context.Context, tx interface{}, ops *types.TransactOpts) (txtypes.TxInfo, error) {
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
 	if value, ok := tx.(*types.MintNftTxReq); ok {
-		return c.constructMintNftTransaction(value, ops)
+		return c.constructMintNftTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.CreateCollectionTxReq); ok {
-		return c.constructCreateCollectionTransaction(value, ops)
+		return c.constructCreateCollectionTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.CancelOfferTxReq); ok {
-		return c.constructCancelOfferTransaction(value, ops)
+		return c.constructCancelOfferTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.OfferTxInfo); ok {
 		return c.constructOfferTxInfoTransaction(value, ops)
 	} else if value, ok := tx.(*types.TransferTxReq); ok {
-		return c.constructTransferTransaction(value, ops)
+		return c.constructTransferTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.TransferNftTxReq); ok {
-		return c.constructTransferNftTransaction(value, ops)
+		return c.constructTransferNftTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.WithdrawTxReq); ok {
-		return c.constructWithdrawTransaction(value, ops)
+		return c.constructWithdrawTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.WithdrawNftTxReq); ok {
-		return c.constructWithdrawNftTransaction(value, ops)
+		return c.constructWithdrawNftTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.UpdateNftReq); ok {
-		return c.constructUpdateNFTTransaction(value, ops)
+		return c.constructUpdateNFTTransaction(ctx, value, ops)
 	} else if value, ok := tx.(*types.ChangePubKeyReq); ok {
-		return c.constructChangePubKeyTransaction(value, ops)
+		return c.constructChangePubKeyTransaction(ctx, value, ops)
 	}
 	return nil, errors.New("invalid tx type is passed")
 }
 
-func (c *l2Client) constructChangePubKeyTransaction(tx *types.ChangePubKeyReq, ops *types.TransactOpts) (*txtypes.ChangePubKeyInfo, error) {
+func (c *l2Client) constructChangePubKeyTransaction(ctx context.Context, tx *types.ChangePubKeyReq, ops *types.TransactOpts) (*txtypes.ChangePubKeyInfo, error) {
 	ops.TxType = txtypes.TxTypeChangePubKey
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1324,9 +989,9 @@ func (c *l2Client) constructChangePubKeyTransaction(tx *types.ChangePubKeyReq, o
 	return txInfo, nil
 }
 
-func (c *l2Client) constructMintNftTransaction(tx *types.MintNftTxReq, ops *types.TransactOpts) (*txtypes.MintNftTxInfo, error) {
+func (c *l2Client) constructMintNftTransaction(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts) (*txtypes.MintNftTxInfo, error) {
 	ops.TxType = txtypes.TxTypeMintNft
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1339,9 +1004,9 @@ func (c *l2Client) constructMintNftTransaction(tx *types.MintNftTxReq, ops *type
 	return txInfo, nil
 }
 
-func (c *l2Client) constructCancelOfferTransaction(tx *types.CancelOfferTxReq, ops *types.TransactOpts) (*txtypes.CancelOfferTxInfo, error) {
+func (c *l2Client) constructCancelOfferTransaction(ctx context.Context, tx *types.CancelOfferTxReq, ops *types.TransactOpts) (*txtypes.CancelOfferTxInfo, error) {
 	ops.TxType = txtypes.TxTypeCancelOffer
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1352,9 +1017,9 @@ func (c *l2Client) constructCancelOfferTransaction(tx *types.CancelOfferTxReq, o
 	return txInfo, nil
 }
 
-func (c *l2Client) constructCreateCollectionTransaction(tx *types.CreateCollectionTxReq, ops *types.TransactOpts) (*txtypes.CreateCollectionTxInfo, error) {
+func (c *l2Client) constructCreateCollectionTransaction(ctx context.Context, tx *types.CreateCollectionTxReq, ops *types.TransactOpts) (*txtypes.CreateCollectionTxInfo, error) {
 	ops.TxType = txtypes.TxTypeCreateCollection
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1365,9 +1030,9 @@ func (c *l2Client) constructCreateCollectionTransaction(tx *types.CreateCollecti
 	return txInfo, nil
 }
 
-func (c *l2Client) constructAtomicMatchTransaction(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*txtypes.AtomicMatchTxInfo, error) {
+func (c *l2Client) constructAtomicMatchTransaction(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*txtypes.AtomicMatchTxInfo, error) {
 	ops.TxType = txtypes.TxTypeAtomicMatch
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1397,9 +1062,9 @@ func (c *l2Client) constructOfferTxInfoTransaction(tx *types.OfferTxInfo, ops *t
 	}, nil
 }
 
-func (c *l2Client) constructTransferNftTransaction(tx *types.TransferNftTxReq, ops *types.TransactOpts) (*txtypes.TransferNftTxInfo, error) {
+func (c *l2Client) constructTransferNftTransaction(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts) (*txtypes.TransferNftTxInfo, error) {
 	ops.TxType = txtypes.TxTypeTransferNft
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1411,9 +1076,9 @@ func (c *l2Client) constructTransferNftTransaction(tx *types.TransferNftTxReq, o
 	return txInfo, nil
 }
 
-func (c *l2Client) constructTransferTransaction(tx *types.TransferTxReq, ops *types.TransactOpts) (*txtypes.TransferTxInfo, error) {
+func (c *l2Client) constructTransferTransaction(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts) (*txtypes.TransferTxInfo, error) {
 	ops.TxType = txtypes.TxTypeTransfer
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1425,9 +1090,9 @@ func (c *l2Client) constructTransferTransaction(tx *types.TransferTxReq, ops *ty
 	return txInfo, nil
 }
 
-func (c *l2Client) constructWithdrawTransaction(tx *types.WithdrawTxReq, ops *types.TransactOpts) (*txtypes.WithdrawTxInfo, error) {
+func (c *l2Client) constructWithdrawTransaction(ctx context.Context, tx *types.WithdrawTxReq, ops *types.TransactOpts) (*txtypes.WithdrawTxInfo, error) {
 	ops.TxType = txtypes.TxTypeWithdraw
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1439,9 +1104,9 @@ func (c *l2Client) constructWithdrawTransaction(tx *types.WithdrawTxReq, ops *ty
 	return txInfo, nil
 }
 
-func (c *l2Client) constructWithdrawNftTransaction(tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*txtypes.WithdrawNftTxInfo, error) {
+func (c *l2Client) constructWithdrawNftTransaction(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*txtypes.WithdrawNftTxInfo, error) {
 	ops.TxType = txtypes.TxTypeWithdrawNft
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return nil, err
 	}
@@ -1453,16 +1118,16 @@ func (c *l2Client) constructWithdrawNftTransaction(tx *types.WithdrawNftTxReq, o
 	return txInfo, nil
 }
 
-func (c *l2Client) constructUpdateNFTTransaction(req *types.UpdateNftReq, ops *types.TransactOpts) (*txtypes.UpdateNFTTxInfo, error) {
+func (c *l2Client) constructUpdateNFTTransaction(ctx context.Context, req *types.UpdateNftReq, ops *types.TransactOpts) (*txtypes.UpdateNFTTxInfo, error) {
 	if req.AccountIndex == 0 {
-		l2Account, err := c.GetAccountByL1Address(c.address)
+		l2Account, err := c.getAccountIndexByL1Address(ctx, c.address)
 		if err != nil {
 			return nil, err
 		}
 		req.AccountIndex = l2Account.Index
 	}
 	if req.Nonce == 0 {
-		nonce, err := c.GetNftNextNonce(req.NftIndex)
+		nonce, err := c.getNftNextNonce(ctx, req.NftIndex)
 		if err != nil {
 			return nil, err
 		}
@@ -1475,13 +1140,82 @@ func (c *l2Client) constructUpdateNFTTransaction(req *types.UpdateNftReq, ops *t
 	return updateNFTTxInfo, nil
 }
 
-func (c *l2Client) parseResultStatus(respBody []byte) error {
+// get sends a GET request for the given api path and decodes the response into result.
+// Queries fail over to the next healthy endpoint and are retried according to the retry
+// policy of the client.
+func (c *l2Client) get(ctx context.Context, path string, result interface{}) error {
+	return c.withRetry(ctx, func() error {
+		return c.withEndpoint(true, func(endpoint string) error {
+			return c.getAt(ctx, endpoint, path, result)
+		})
+	})
+}
+
+// getAt sends a GET request for the given api path to the given endpoint only, without retry.
+func (c *l2Client) getAt(ctx context.Context, endpoint, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, c.queryLimiter, result)
+}
+
+// postForm sends a form encoded POST request for the given api path and decodes the response into result.
+func (c *l2Client) postForm(ctx context.Context, path string, data url.Values, result interface{}) error {
+	return c.withEndpoint(false, func(endpoint string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, strings.NewReader(data.Encode()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return c.do(req, c.queryLimiter, result)
+	})
+}
+
+// do sends the request once the limiter, if any, allows it.
+func (c *l2Client) do(req *http.Request, limiter *Limiter, result interface{}) error {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	release, err := c.waitLimiter(req, limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = HttpClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, req.URL.Path, body)
+	}
+	if err = c.parseResultStatus(req.URL.Path, body); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
+
+func (c *l2Client) parseResultStatus(path string, respBody []byte) error {
 	resultStatus := &types.Result{}
 	if err := json.Unmarshal(respBody, resultStatus); err != nil {
 		return err
 	}
 	if resultStatus.Code != types.CodeOK {
-		return errors.New(resultStatus.Message)
+		return &APIError{
+			StatusCode: http.StatusOK,
+			Code:       resultStatus.Code,
+			Message:    resultStatus.Message,
+			Path:       path,
+		}
 	}
 	return nil
 }
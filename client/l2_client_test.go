@@ -1,11 +1,14 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/bnb-chain/zkbnb-crypto/ffmath"
 	"github.com/ethereum/go-ethereum/crypto"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -22,7 +25,7 @@ var privateKey = l1PrivateKey
 func prepareSdkClientWithPrivateKey() *l2Client {
 	sdkClient, err := NewZkBNBClientWithPrivateKey(testEndpoint, privateKey, chainNetworkId)
 	if err != nil {
-		fmt.Errorf("error Occurred when Creating ZKBNB client! error:%s", err.Error())
+		fmt.Printf("error Occurred when Creating ZKBNB client! error:%s\n", err.Error())
 		return nil
 	}
 	return sdkClient.(*l2Client)
@@ -40,7 +43,7 @@ func prepareSdkClientWithSeed() *l2Client {
 	address := crypto.PubkeyToAddress(privateKeyInEcdsa.PublicKey)
 	sdkClient, err := NewZkBNBClientNoAuthorized(testEndpoint, seed, address.Hex(), chainNetworkId)
 	if err != nil {
-		fmt.Errorf("error Occurred when Creating ZKBNB client! error:%s", err.Error())
+		fmt.Printf("error Occurred when Creating ZKBNB client! error:%s\n", err.Error())
 		return nil
 	}
 	return sdkClient.(*l2Client)
@@ -92,6 +95,27 @@ func TestGetCurrentHeight(t *testing.T) {
 	println("current height: ", height)
 }
 
+func TestGetCurrentHeightWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":100,"message":"","height":10}`))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+	height, err := sdkClient.GetCurrentHeightWithContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sdkClient.GetCurrentHeightWithContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = sdkClient.TransferWithContext(ctx, &types.TransferTxReq{To: l1Address, AssetAmount: big.NewInt(1)}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestGetAsset(t *testing.T) {
 	sdkClient := prepareSdkClientWithPrivateKey()
 	asset, err := sdkClient.GetAssetBySymbol("BNB")
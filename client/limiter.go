@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const sendTxPath = "/api/v1/sendTx"
+
+// Limiter combines a token bucket rate limiter with a limit on the number of requests
+// in flight. It is safe for concurrent use and can be shared between clients.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+}
+
+// NewLimiter returns a limiter allowing requestsPerSecond requests on average with bursts
+// of up to burst requests, and at most maxInFlight concurrent requests. A requestsPerSecond
+// or maxInFlight <= 0 disables the corresponding limit.
+func NewLimiter(requestsPerSecond float64, burst int, maxInFlight int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &Limiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// Wait blocks until a request may be sent. It returns a release function which must be
+// called once the request is done, and the time spent waiting.
+func (l *Limiter) Wait(ctx context.Context) (release func(), waited time.Duration, err error) {
+	start := time.Now()
+	if delay := l.reserve(); delay > 0 {
+		if err := sleepContext(ctx, delay); err != nil {
+			l.cancel()
+			return nil, time.Since(start), err
+		}
+	}
+	if l.inFlight == nil {
+		return func() {}, time.Since(start), nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, time.Since(start), ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-l.inFlight })
+	}, time.Since(start), nil
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (l *Limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token reserved by a request which was not sent.
+func (l *Limiter) cancel() {
+	if l.rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// waitLimiter waits for the limiter chosen by the caller of the request, sendRawTx uses the
+// sendTx limiter and the other requests the query limiter.
+func (c *l2Client) waitLimiter(req *http.Request, limiter *Limiter) (func(), error) {
+	if limiter == nil {
+		return func() {}, nil
+	}
+	release, waited, err := limiter.Wait(req.Context())
+	if c.limiterHook != nil {
+		c.limiterHook(req.URL.Path, waited)
+	}
+	return release, err
+}
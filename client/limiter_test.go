@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterRate(t *testing.T) {
+	limiter := NewLimiter(100, 1, 0)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, _, err := limiter.Wait(context.Background())
+		assert.NoError(t, err)
+		release()
+	}
+	// the first token is available immediately, the 4 others take 10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = NewLimiter(1, 1, 0)
+	_, _, err := limiter.Wait(ctx)
+	assert.NoError(t, err)
+	_, _, err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLimiterInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte(`{"code":100,"message":"","height":10}`))
+	}))
+	defer server.Close()
+
+	var hookCalls int32
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId,
+		WithQueryLimiter(NewLimiter(0, 1, 2)),
+		WithLimiterHook(func(path string, waited time.Duration) {
+			assert.Equal(t, "/api/v1/currentHeight", path)
+			atomic.AddInt32(&hookCalls, 1)
+		}))
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sdkClient.GetCurrentHeight()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, maxInFlight, int32(2))
+	assert.Equal(t, int32(6), hookCalls)
+}
+
+func TestLimiterBudgets(t *testing.T) {
+	var healthChecks int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Path, "/zkbnb/api/v1/")
+		if r.URL.Path == "/zkbnb/api/v1/currentHeight" {
+			atomic.AddInt32(&healthChecks, 1)
+		}
+		_, _ = w.Write([]byte(`{"code":100,"message":"","height":10,"tx_hash":"0x01"}`))
+	})
+	primary := httptest.NewServer(handler)
+	defer primary.Close()
+	secondary := httptest.NewServer(handler)
+	defer secondary.Close()
+
+	// the query budget only allows one request, the endpoints have a path prefix
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(primary.URL+"/zkbnb", privateKey, chainNetworkId,
+		WithQueryLimiter(NewLimiter(0.001, 1, 0)),
+		WithSendTxLimiter(NewLimiter(0, 1, 0)),
+		WithFallbackEndpoints(secondary.URL+"/zkbnb"))
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = sdkClient.GetTxWithContext(ctx, "0x01")
+	assert.NoError(t, err)
+	// the health checks of both endpoints do not use the query budget
+	for atomic.LoadInt32(&healthChecks) < 2 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&healthChecks))
+
+	// sendTx has its own budget
+	_, err = sdkClient.SendRawTxWithContext(ctx, 4, "{}")
+	assert.NoError(t, err)
+	_, err = sdkClient.GetTxWithContext(ctx, "0x01")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
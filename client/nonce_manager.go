@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+)
+
+// NonceManager hands out the nonces of L2 accounts locally, so that txs can be built and sent
+// concurrently without asking ZkBNB for the next nonce each time. The next nonce of an account
+// is synced from GetNextNonce and the pending txs of the account the first time it is used and
+// after Resync or Reset, e.g. when ZkBNB rejects a tx because of its nonce. It is safe for
+// concurrent use.
+type NonceManager struct {
+	querier ZkBNBContextQuerier
+
+	mu       sync.Mutex
+	accounts map[int64]*accountNonces
+}
+
+type accountNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     int64
+	released []int64
+	reserved map[int64]struct{}
+}
+
+// NewNonceManager returns a nonce manager syncing the nonces from querier.
+func NewNonceManager(querier ZkBNBContextQuerier) *NonceManager {
+	return &NonceManager{
+		querier:  querier,
+		accounts: make(map[int64]*accountNonces),
+	}
+}
+
+func (m *NonceManager) account(accountIndex int64) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[accountIndex]
+	if !ok {
+		a = &accountNonces{reserved: make(map[int64]struct{})}
+		m.accounts[accountIndex] = a
+	}
+	return a
+}
+
+// Reserve returns the next nonce of the account. Released nonces are handed out again first,
+// lowest first, so that no gap is left behind.
+func (m *NonceManager) Reserve(ctx context.Context, accountIndex int64) (int64, error) {
+	a := m.account(accountIndex)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.synced {
+		if err := m.sync(ctx, accountIndex, a); err != nil {
+			return 0, err
+		}
+	}
+	var nonce int64
+	if len(a.released) > 0 {
+		nonce, a.released = a.released[0], a.released[1:]
+	} else {
+		nonce = a.next
+		a.next++
+	}
+	a.reserved[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Release gives back a reserved nonce whose tx was not accepted by ZkBNB.
+func (m *NonceManager) Release(accountIndex int64, nonce int64) {
+	a := m.account(accountIndex)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.reserved[nonce]; !ok {
+		return
+	}
+	delete(a.reserved, nonce)
+	i := sort.Search(len(a.released), func(i int) bool { return a.released[i] >= nonce })
+	a.released = append(a.released, 0)
+	copy(a.released[i+1:], a.released[i:])
+	a.released[i] = nonce
+}
+
+// Resync drops the local state of the account and fetches its next nonce from ZkBNB.
+func (m *NonceManager) Resync(ctx context.Context, accountIndex int64) error {
+	a := m.account(accountIndex)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return m.sync(ctx, accountIndex, a)
+}
+
+// Reset drops the local state of the account, it is synced again by the next Reserve.
+func (m *NonceManager) Reset(accountIndex int64) {
+	a := m.account(accountIndex)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.synced = false
+}
+
+func (m *NonceManager) sync(ctx context.Context, accountIndex int64, a *accountNonces) error {
+	next, err := m.querier.GetNextNonceWithContext(ctx, accountIndex)
+	if err != nil {
+		return err
+	}
+	account, err := m.querier.GetAccountByIndexWithContext(ctx, accountIndex)
+	if err != nil {
+		return err
+	}
+	_, pendingTxs, err := m.querier.GetPendingTxsByL1AddressWithContext(ctx, account.L1Address)
+	if err != nil {
+		return err
+	}
+	for _, tx := range pendingTxs {
+		if tx.AccountIndex == accountIndex && tx.Nonce >= next {
+			next = tx.Nonce + 1
+		}
+	}
+	a.synced = true
+	a.next = next
+	a.released = nil
+	a.reserved = make(map[int64]struct{})
+	return nil
+}
+
+// settle updates the nonce manager with the outcome of sending a tx. A nonce is only released
+// when ZkBNB answered and rejected the tx, with any status but a 5xx, a tx whose outcome is
+// unknown keeps its nonce.
+func (m *NonceManager) settle(txType uint32, txInfo string, sendErr error) {
+	tx, err := txutils.ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return
+	}
+	accountIndex, nonce := tx.GetFromAccountIndex(), tx.GetNonce()
+
+	var apiErr *APIError
+	switch {
+	case sendErr == nil:
+		a := m.account(accountIndex)
+		a.mu.Lock()
+		delete(a.reserved, nonce)
+		a.mu.Unlock()
+	case errors.As(sendErr, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError:
+		m.Release(accountIndex, nonce)
+	}
+}
+
+// txNonce is the nonce reserved from the nonce manager while a tx method builds its tx. The tx
+// methods release it when they fail before the tx is sent, once sent it is settled instead.
+// The sign body generators do not reserve nonces since their tx is sent by someone else.
+type txNonce struct {
+	manager      *NonceManager
+	accountIndex int64
+	nonce        int64
+	reserved     bool
+	sent         bool
+}
+
+type txNonceKey struct{}
+
+// withTxNonce returns a context in which fullFillDefaultOps reserves the nonce of the tx from
+// the nonce manager, if any.
+func (c *l2Client) withTxNonce(ctx context.Context) (context.Context, *txNonce) {
+	n := &txNonce{manager: c.nonceManager}
+	return context.WithValue(ctx, txNonceKey{}, n), n
+}
+
+func (n *txNonce) reserve(ctx context.Context, accountIndex int64) (int64, error) {
+	nonce, err := n.manager.Reserve(ctx, accountIndex)
+	if err != nil {
+		return 0, err
+	}
+	n.accountIndex, n.nonce, n.reserved = accountIndex, nonce, true
+	return nonce, nil
+}
+
+// release gives back the reserved nonce unless the tx was sent.
+func (n *txNonce) release() {
+	if n.reserved && !n.sent {
+		n.manager.Release(n.accountIndex, n.nonce)
+	}
+}
+
+// sendTx sends a tx built by a tx method, the nonce is settled by SendRawTx from then on.
+func (c *l2Client) sendTx(ctx context.Context, nonce *txNonce, txType uint32, txInfo string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	nonce.sent = true
+	return c.SendRawTxWithContext(ctx, txType, txInfo)
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func nonceServer(nextNonce, pendingNonce *int64, sendResult *atomic.Value) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			_, _ = fmt.Fprintf(w, `{"code":100,"nonce":%d}`, atomic.LoadInt64(nextNonce))
+		case "/api/v1/account":
+			_, _ = fmt.Fprintf(w, `{"code":100,"index":2,"l1_address":"%s"}`, l1Address)
+		case "/api/v1/accountPendingTxs":
+			_, _ = fmt.Fprintf(w, `{"code":100,"total":1,"txs":[{"account_index":2,"nonce":%d}]}`, atomic.LoadInt64(pendingNonce))
+		case sendTxPath:
+			result := sendResult.Load().(string)
+			// a result may start with the http status of the response
+			if status, err := strconv.Atoi(result[:3]); err == nil {
+				w.WriteHeader(status)
+				result = result[3:]
+			}
+			_, _ = w.Write([]byte(result))
+		}
+	}))
+}
+
+func TestNonceManagerReserve(t *testing.T) {
+	var nextNonce, pendingNonce int64 = 3, 4
+	var sendResult atomic.Value
+	server := nonceServer(&nextNonce, &pendingNonce, &sendResult)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithNonceManager())
+	assert.NoError(t, err)
+	manager := sdkClient.NonceManager()
+
+	// concurrent senders get distinct nonces following the pending txs
+	var mu sync.Mutex
+	nonces := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := manager.Reserve(context.Background(), 2)
+			assert.NoError(t, err)
+			mu.Lock()
+			nonces[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, nonces, 50)
+	for nonce := int64(5); nonce < 55; nonce++ {
+		assert.True(t, nonces[nonce])
+	}
+
+	// released nonces are reused first, unknown ones are ignored
+	manager.Release(2, 9)
+	manager.Release(2, 7)
+	manager.Release(2, 100)
+	for _, expected := range []int64{7, 9, 55} {
+		nonce, err := manager.Reserve(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, nonce)
+	}
+
+	atomic.StoreInt64(&nextNonce, 20)
+	atomic.StoreInt64(&pendingNonce, 0)
+	assert.NoError(t, manager.Resync(context.Background(), 2))
+	nonce, err := manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), nonce)
+}
+
+func TestNonceManagerSendRawTx(t *testing.T) {
+	// signedTransferTxInfo is signed with nonce 1 from account 2
+	var nextNonce, pendingNonce int64 = 1, 0
+	var sendResult atomic.Value
+	server := nonceServer(&nextNonce, &pendingNonce, &sendResult)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithNonceManager())
+	assert.NoError(t, err)
+	manager := sdkClient.NonceManager()
+	txInfo := signedTransferTxInfo(t)
+
+	// a rejected tx gives its nonce back
+	nonce, err := manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nonce)
+	sendResult.Store(`{"code":21002,"message":"balance is not enough"}`)
+	_, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.ErrorIs(t, err, &APIError{Code: 21002})
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nonce)
+	// whatever the status of the response
+	sendResult.Store(`400{"code":21002,"message":"balance is not enough"}`)
+	_, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.ErrorIs(t, err, &APIError{Code: 21002})
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nonce)
+	// but a server error keeps it, the tx may have been accepted
+	sendResult.Store(`502`)
+	_, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.ErrorIs(t, err, ErrServerError)
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), nonce)
+	manager.Release(2, nonce)
+
+	// an accepted tx keeps it
+	sendResult.Store(`{"code":100,"tx_hash":"abc"}`)
+	_, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), nonce)
+
+	// the account is only synced again by Resync
+	atomic.StoreInt64(&nextNonce, 10)
+	sendResult.Store(`{"code":21001,"message":"invalid nonce"}`)
+	_, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.ErrorIs(t, err, &APIError{Code: 21001})
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), nonce)
+	assert.NoError(t, manager.Resync(context.Background(), 2))
+	nonce, err = manager.Reserve(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), nonce)
+}
+
+func TestNonceManagerTxMethods(t *testing.T) {
+	var nextNonce, pendingNonce int64 = 1, 0
+	var sendResult atomic.Value
+	server := nonceServer(&nextNonce, &pendingNonce, &sendResult)
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithNonceManager())
+	assert.NoError(t, err)
+	manager := sdkClient.NonceManager()
+	expectNextNonce := func(expected int64) {
+		nonce, err := manager.Reserve(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, nonce)
+		manager.Release(2, nonce)
+	}
+	newOps := func() *types.TransactOpts {
+		return &types.TransactOpts{FromAccountIndex: 2, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(1000)}
+	}
+	transfer := &types.TransferTxReq{To: l1Address, AssetAmount: big.NewInt(100)}
+	expectNextNonce(1)
+
+	// the nonce is released when building the tx fails after it was reserved
+	ops := newOps()
+	ops.GasFeeAssetAmount = nil
+	_, err = sdkClient.Transfer(transfer, ops)
+	assert.Error(t, err)
+	expectNextNonce(1)
+
+	// or when the context is cancelled before the tx is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sdkClient.TransferWithContext(ctx, transfer, newOps())
+	assert.ErrorIs(t, err, context.Canceled)
+	expectNextNonce(1)
+
+	// the sign body generators do not reserve nonces
+	_, err = sdkClient.GenerateSignBody(transfer, newOps())
+	assert.NoError(t, err)
+	_, err = sdkClient.GenerateSignatureWithContext(context.Background(), privateKey, transfer, newOps())
+	assert.NoError(t, err)
+	expectNextNonce(1)
+
+	sendResult.Store(`{"code":100,"tx_hash":"abc"}`)
+	_, err = sdkClient.Transfer(transfer, newOps())
+	assert.NoError(t, err)
+	expectNextNonce(2)
+}
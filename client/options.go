@@ -0,0 +1,250 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultRequestTimeout = 10 * time.Second
+	defaultDialTimeout    = 1 * time.Second
+)
+
+type clientOption struct {
+	channelName string
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	proxy       func(*http.Request) (*url.URL, error)
+	timeout     time.Duration
+	dialTimeout time.Duration
+	userAgent   string
+	retryPolicy RetryPolicy
+
+	queryLimiter  *Limiter
+	sendTxLimiter *Limiter
+	limiterHook   func(path string, waited time.Duration)
+
+	nonceManager bool
+	cache        Cache
+	cacheTTL     time.Duration
+
+	fallbackEndpoints   []string
+	healthCheckInterval time.Duration
+	maxHeightLag        int64
+
+	typedDataSigning bool
+	zkbnbContract    string
+}
+
+type ClientOptionFunc func(*clientOption)
+
+// WithChannelName sets the channel name sent along with every tx.
+func WithChannelName(channelName string) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.channelName = channelName
+	}
+}
+
+// WithHttpClient makes the client use the given http client as is, the tls, proxy and
+// timeout options are ignored in that case.
+func WithHttpClient(httpClient *http.Client) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTLSConfig sets the tls config of the client transport. Certificates are verified
+// against the system roots unless the config says otherwise.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.tlsConfig = tlsConfig.Clone()
+	}
+}
+
+// WithRootCAs pins the certificate authorities used to verify the server certificate.
+func WithRootCAs(pool *x509.CertPool) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.ensureTLSConfig().RootCAs = pool
+	}
+}
+
+// WithClientCertificates sets the certificates presented to the server for mutual tls.
+func WithClientCertificates(certs ...tls.Certificate) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.ensureTLSConfig().Certificates = certs
+	}
+}
+
+// WithProxy sets the proxy function of the client transport, e.g. http.ProxyFromEnvironment
+// or http.ProxyURL.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.proxy = proxy
+	}
+}
+
+// WithTimeout sets the overall timeout of a single request, 10s by default.
+func WithTimeout(timeout time.Duration) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.timeout = timeout
+	}
+}
+
+// WithDialTimeout sets the timeout for establishing a connection, 1s by default.
+func WithDialTimeout(timeout time.Duration) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy retries failed queries according to the policy, e.g. NewExponentialBackoff().
+// Txs are only sent again when the previous attempt provably did not reach ZkBNB.
+// Calls are not retried by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithQueryLimiter limits the rate and concurrency of all requests except /api/v1/sendTx and
+// the endpoint health checks.
+func WithQueryLimiter(limiter *Limiter) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.queryLimiter = limiter
+	}
+}
+
+// WithSendTxLimiter limits the rate and concurrency of /api/v1/sendTx requests.
+func WithSendTxLimiter(limiter *Limiter) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.sendTxLimiter = limiter
+	}
+}
+
+// WithLimiterHook registers a function called with the time each request waited for its limiter.
+func WithLimiterHook(hook func(path string, waited time.Duration)) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.limiterHook = hook
+	}
+}
+
+// WithNonceManager makes the client reserve the nonces of its txs locally with a NonceManager
+// instead of calling GetNextNonce before each tx.
+func WithNonceManager() ClientOptionFunc {
+	return func(o *clientOption) {
+		o.nonceManager = true
+	}
+}
+
+// WithCache caches the gas account, the account indexes of L1 addresses, the assets and the gas
+// fees for ttl. A nil cache uses a MemoryCache. Cached values can be dropped with InvalidateCache.
+func WithCache(cache Cache, ttl time.Duration) ClientOptionFunc {
+	return func(o *clientOption) {
+		if cache == nil {
+			cache = NewMemoryCache()
+		}
+		o.cache = cache
+		o.cacheTTL = ttl
+	}
+}
+
+// WithFallbackEndpoints adds ZkBNB api endpoints next to the one the client is created with.
+// Requests go to the healthiest endpoint and queries fail over to the next one on transient
+// errors.
+func WithFallbackEndpoints(urls ...string) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.fallbackEndpoints = append(o.fallbackEndpoints, urls...)
+	}
+}
+
+// WithHealthCheck sets how often the endpoints are health checked with GetCurrentHeight, 10s
+// by default, and how many blocks an endpoint may lag behind the highest one before it is
+// avoided, 10 by default. An interval <= 0 disables the health checks, the endpoints are then
+// only avoided for 10s after failing. It only matters together with WithFallbackEndpoints.
+func WithHealthCheck(interval time.Duration, maxHeightLag int64) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.healthCheckInterval = interval
+		o.maxHeightLag = maxHeightLag
+	}
+}
+
+func newClientOption(options []ClientOptionFunc) *clientOption {
+	opt := &clientOption{
+		timeout:     defaultRequestTimeout,
+		dialTimeout: defaultDialTimeout,
+
+		healthCheckInterval: defaultHealthCheckInterval,
+		maxHeightLag:        defaultMaxHeightLag,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	return opt
+}
+
+func (o *clientOption) apply(c *l2Client) {
+	c.channelName = o.channelName
+	c.httpClient = o.newHttpClient()
+	c.userAgent = o.userAgent
+	c.retryPolicy = o.retryPolicy
+	c.queryLimiter = o.queryLimiter
+	c.sendTxLimiter = o.sendTxLimiter
+	c.limiterHook = o.limiterHook
+	c.typedDataSigning = o.typedDataSigning
+	c.zkbnbContract = o.zkbnbContract
+	if o.cache != nil {
+		c.cache = &clientCache{backend: o.cache, ttl: o.cacheTTL, namespace: c.endpoint}
+	}
+	if o.nonceManager {
+		c.nonceManager = NewNonceManager(c)
+	}
+	if len(o.fallbackEndpoints) > 0 {
+		urls := append([]string{c.endpoint}, o.fallbackEndpoints...)
+		c.endpoints = newEndpointPool(urls, o.healthCheckInterval, o.timeout, o.maxHeightLag)
+		c.endpoints.check = c.getEndpointHeight
+	}
+}
+
+func (o *clientOption) ensureTLSConfig() *tls.Config {
+	if o.tlsConfig == nil {
+		o.tlsConfig = &tls.Config{}
+	}
+	return o.tlsConfig
+}
+
+func (o *clientOption) newHttpClient() *http.Client {
+	if o.httpClient != nil {
+		return o.httpClient
+	}
+	if o.tlsConfig == nil && o.proxy == nil && o.timeout == defaultRequestTimeout && o.dialTimeout == defaultDialTimeout {
+		// the shared HttpClient is used
+		return nil
+	}
+	dialer := &net.Dialer{
+		Timeout:   o.dialTimeout,
+		KeepAlive: 60 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:               o.proxy,
+		DialContext:         dialer.DialContext,
+		MaxConnsPerHost:     1000,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     10 * time.Second,
+		TLSClientConfig:     o.tlsConfig,
+	}
+	return &http.Client{
+		Timeout:   o.timeout,
+		Transport: transport,
+	}
+}
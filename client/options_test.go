@@ -0,0 +1,64 @@
+package client
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientVerifiesCertificateByDefault(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":100,"message":"","height":10}`))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+	_, err = sdkClient.GetCurrentHeight()
+	assert.Error(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	sdkClient, err = NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithRootCAs(pool))
+	assert.NoError(t, err)
+	height, err := sdkClient.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+}
+
+func TestClientOptions(t *testing.T) {
+	var userAgent, channelName string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		channelName = r.Header.Get("Channel-Name")
+		_, _ = w.Write([]byte(`{"code":100,"message":"","tx_hash":"0x01"}`))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId,
+		WithUserAgent("zkbnb-bot/1.0"), WithChannelName("my-channel"))
+	assert.NoError(t, err)
+	hash, err := sdkClient.SendRawTx(4, "{}")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x01", hash)
+	assert.Equal(t, "zkbnb-bot/1.0", userAgent)
+	assert.Equal(t, "my-channel", channelName)
+
+	custom := &http.Client{}
+	sdkClient, err = NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithHttpClient(custom))
+	assert.NoError(t, err)
+	assert.Same(t, custom, sdkClient.(*l2Client).httpClient)
+
+	// the channel name is still accepted as before, with the shared HttpClient
+	sdkClient, err = NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId, "old-channel")
+	assert.NoError(t, err)
+	assert.Nil(t, sdkClient.(*l2Client).httpClient)
+	_, err = sdkClient.SendRawTx(4, "{}")
+	assert.NoError(t, err)
+	assert.Equal(t, "old-channel", channelName)
+	_, err = NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId, "a", "b")
+	assert.Error(t, err)
+}
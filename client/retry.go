@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// RetryPolicy decides whether a failed api call is retried and how long to wait before.
+type RetryPolicy interface {
+	// NextBackoff is called after the attempt-th call (starting from 1) failed with err,
+	// it returns the delay before the next call and false if the call should not be retried.
+	NextBackoff(attempt int, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff retries transient errors with an exponentially growing delay.
+type ExponentialBackoff struct {
+	// MaxAttempts is the maximum number of calls, including the first one
+	MaxAttempts int
+	// InitialInterval is the delay before the first retry
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between two calls
+	MaxInterval time.Duration
+	// Multiplier is applied to the delay after each retry
+	Multiplier float64
+	// Jitter randomizes the delay by up to the given fraction, e.g. 0.2 for +/-20%
+	Jitter float64
+}
+
+// NewExponentialBackoff returns a policy making at most 4 calls, waiting 200ms, 400ms and 800ms
+// (+/-20%) between them.
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxAttempts:     4,
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+func (b *ExponentialBackoff) NextBackoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= b.MaxAttempts || !IsRetryable(err) {
+		return 0, false
+	}
+	delay := float64(b.InitialInterval) * math.Pow(b.Multiplier, float64(attempt-1))
+	if b.MaxInterval > 0 && delay > float64(b.MaxInterval) {
+		delay = float64(b.MaxInterval)
+	}
+	if b.Jitter > 0 {
+		delay += delay * b.Jitter * (2*rand.Float64() - 1)
+	}
+	backoff := time.Duration(delay)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > backoff {
+		backoff = apiErr.RetryAfter
+	}
+	return backoff, true
+}
+
+// IsRetryable reports whether err is a transient failure: a transport error, a 5xx
+// response or a 429 response. Errors caused by a cancelled context are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	var transportErr *transportError
+	return errors.As(err, &transportErr)
+}
+
+// transportError marks the errors returned by the http client, e.g. dial failures
+// or connection resets, as opposed to errors decoding the response.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// withRetry calls call until it succeeds or the retry policy gives up.
+func (c *l2Client) withRetry(ctx context.Context, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || c.retryPolicy == nil {
+			return err
+		}
+		backoff, ok := c.retryPolicy.NextBackoff(attempt, err)
+		if !ok {
+			return err
+		}
+		if sleepErr := sleepContext(ctx, backoff); sleepErr != nil {
+			return fmt.Errorf("%w, last error: %v", sleepErr, err)
+		}
+	}
+}
+
+// sendRawTxWithRetry sends the tx and only sends it again when ZkBNB provably does not
+// know it, so that a tx whose response got lost is never submitted twice. All the attempts
+// and the lookups of the tx go to the same endpoint, another endpoint may lag behind it.
+func (c *l2Client) sendRawTxWithRetry(ctx context.Context, txType uint32, txInfo string) (string, error) {
+	endpoint := c.txEndpoint()
+	for attempt := 1; ; attempt++ {
+		txHash, err := c.sendRawTx(ctx, endpoint, txType, txInfo)
+		if err == nil || c.retryPolicy == nil {
+			return txHash, err
+		}
+		backoff, ok := c.retryPolicy.NextBackoff(attempt, err)
+		if !ok {
+			return "", err
+		}
+
+		localHash, hashErr := txutils.ComputeTxHash(txType, txInfo)
+		if hashErr != nil {
+			return "", err
+		}
+		getErr := c.getAt(ctx, endpoint, fmt.Sprintf("/api/v1/tx?hash=%s", localHash), &types.EnrichedTx{})
+		if getErr == nil {
+			// the previous call reached the server even though it failed on our side
+			return localHash, nil
+		}
+		// the tx is only sent again when ZkBNB answers that it does not know it
+		if !isRejected(getErr) {
+			return "", err
+		}
+
+		if sleepErr := sleepContext(ctx, backoff); sleepErr != nil {
+			return "", fmt.Errorf("%w, last error: %v", sleepErr, err)
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *ExponentialBackoff {
+	return &ExponentialBackoff{MaxAttempts: 3, InitialInterval: time.Millisecond, Multiplier: 2}
+}
+
+func signedTransferTxInfo(t *testing.T) string {
+	seed, err := accounts.GenerateSeed(privateKey, chainNetworkId)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             1,
+		CallDataHash:      append(make([]byte, 31), 1),
+		ToAccountAddress:  l1Address,
+	}
+	txInfo, err := txutils.ConstructTransferTx(keyManager, ops, &types.TransferTxReq{To: l1Address, AssetAmount: big.NewInt(100)})
+	assert.NoError(t, err)
+	txInfoBytes, err := json.Marshal(txInfo)
+	assert.NoError(t, err)
+	return string(txInfoBytes)
+}
+
+func TestRetryQuery(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":100,"message":"","height":10}`))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithRetryPolicy(testRetryPolicy()))
+	assert.NoError(t, err)
+	height, err := sdkClient.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+	assert.Equal(t, int32(3), calls)
+
+	// a call cancelled while backing off returns the context error
+	atomic.StoreInt32(&calls, -10)
+	sdkClient, err = NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId,
+		WithRetryPolicy(&ExponentialBackoff{MaxAttempts: 3, InitialInterval: time.Hour, Multiplier: 2}))
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = sdkClient.GetCurrentHeightWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "503")
+
+	// non transient errors are not retried
+	atomic.StoreInt32(&calls, 0)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"code":21000,"message":"account not found"}`))
+	})
+	_, err = sdkClient.GetAccountByIndex(1)
+	assert.ErrorIs(t, err, &APIError{Code: 21000})
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestRetrySendRawTx(t *testing.T) {
+	txInfo := signedTransferTxInfo(t)
+	txHash, err := txutils.ComputeTxHash(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+
+	var sends int32
+	var accepted atomic.Value
+	accepted.Store(false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			n := atomic.AddInt32(&sends, 1)
+			if n == 1 || accepted.Load().(bool) {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":100,"message":"","tx_hash":"` + txHash + `"}`))
+		case "/api/v1/tx":
+			if accepted.Load().(bool) && r.URL.Query().Get("hash") == txHash {
+				_, _ = w.Write([]byte(`{"code":100,"message":"","hash":"` + txHash + `"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":24000,"message":"tx not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainNetworkId, WithRetryPolicy(testRetryPolicy()))
+	assert.NoError(t, err)
+
+	// the tx is unknown after the first failure, so it is sent again
+	hash, err := sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, hash)
+	assert.Equal(t, int32(2), sends)
+
+	// the tx reached the server even though the response failed, it must not be sent twice
+	atomic.StoreInt32(&sends, 0)
+	accepted.Store(true)
+	hash, err = sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, hash)
+	assert.Equal(t, int32(1), sends)
+}
+
+func TestRetrySendRawTxPinsEndpoint(t *testing.T) {
+	txInfo := signedTransferTxInfo(t)
+	txHash, err := txutils.ComputeTxHash(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+
+	// the primary endpoint accepts the tx but the response is lost
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v1/tx":
+			_, _ = w.Write([]byte(`{"code":100,"message":"","hash":"` + txHash + `"}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":100,"message":"","height":10}`))
+		}
+	}))
+	defer primary.Close()
+	// the lagging fallback endpoint does not know it yet
+	var fallbackCalls int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx", "/api/v1/tx":
+			atomic.AddInt32(&fallbackCalls, 1)
+			_, _ = w.Write([]byte(`{"code":24000,"message":"tx not found"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer fallback.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKeyAndOptions(primary.URL, privateKey, chainNetworkId,
+		WithRetryPolicy(testRetryPolicy()), WithFallbackEndpoints(fallback.URL), WithHealthCheck(time.Hour, 10))
+	assert.NoError(t, err)
+	hash, err := sdkClient.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, hash)
+	assert.Zero(t, atomic.LoadInt32(&fallbackCalls))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	policy := &ExponentialBackoff{MaxAttempts: 3, InitialInterval: 100 * time.Millisecond, MaxInterval: 150 * time.Millisecond, Multiplier: 2}
+	serverErr := &APIError{StatusCode: http.StatusInternalServerError}
+
+	backoff, ok := policy.NextBackoff(1, serverErr)
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, backoff)
+	backoff, ok = policy.NextBackoff(2, serverErr)
+	assert.True(t, ok)
+	assert.Equal(t, 150*time.Millisecond, backoff)
+	_, ok = policy.NextBackoff(3, serverErr)
+	assert.False(t, ok)
+
+	backoff, ok = policy.NextBackoff(1, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second})
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, backoff)
+
+	_, ok = policy.NextBackoff(1, errors.New("invalid json"))
+	assert.False(t, ok)
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// zkbnbContractName is the name of the ZkBNB contract in the contract addresses of the
+// layer 2 basic info.
+const zkbnbContractName = "ZkBNB"
+
+// ZkBNBL1SignModeNegotiator picks the scheme of the L1 signatures of the txs.
+type ZkBNBL1SignModeNegotiator interface {
+	// L1SignMode returns the scheme the L1 signatures of the txs are made with. It is
+	// signer.L1SignModePersonal unless the client was created with
+	// WithExperimentalTypedDataSigning and the server supports EIP-712 typed data.
+	L1SignMode(ctx context.Context) (signer.L1SignMode, error)
+
+	// GenerateTypedData generates the EIP-712 typed data for the caller to sign in the typed
+	// data sign mode, like GenerateSignBody does for the personal sign mode.
+	GenerateTypedData(ctx context.Context, txData interface{}, ops *types.TransactOpts) (*apitypes.TypedData, error)
+}
+
+// WithExperimentalTypedDataSigning makes the client sign the txs with EIP-712 typed data when
+// the server lists signer.L1SignModeTypedData in the l1 sign modes of its layer 2 basic info,
+// and with personal messages otherwise. The domain is separated by the chain id of the client
+// and the address of the ZkBNB contract, an empty address is taken from the layer 2 basic
+// info. The L1 signer has to implement signer.TypedDataSigner.
+//
+// The typed data sign mode is experimental: the l1_sign_modes of the basic info, the
+// l1_sign_mode form field of the txs and the schema of txutils.BuildTypedData are not part of
+// the documented ZkBNB api, only zkbnbtest implements them, and they may change.
+func WithExperimentalTypedDataSigning(zkbnbContract string) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.typedDataSigning = true
+		o.zkbnbContract = zkbnbContract
+	}
+}
+
+// l1SignMode is the outcome of the sign mode negotiation, kept once it succeeded.
+type l1SignMode struct {
+	mode   signer.L1SignMode
+	domain apitypes.TypedDataDomain
+}
+
+func (c *l2Client) L1SignMode(ctx context.Context) (signer.L1SignMode, error) {
+	signMode, err := c.negotiateL1SignMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	return signMode.mode, nil
+}
+
+func (c *l2Client) negotiateL1SignMode(ctx context.Context) (*l1SignMode, error) {
+	if !c.typedDataSigning {
+		return &l1SignMode{mode: signer.L1SignModePersonal}, nil
+	}
+	c.signModeMu.Lock()
+	defer c.signModeMu.Unlock()
+	if c.signMode != nil {
+		return c.signMode, nil
+	}
+
+	info, err := c.GetLayer2BasicInfoWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("negotiate the l1 sign mode: %w", err)
+	}
+	signMode := &l1SignMode{mode: signer.L1SignModePersonal}
+	for _, mode := range info.L1SignModes {
+		if signer.L1SignMode(mode) != signer.L1SignModeTypedData {
+			continue
+		}
+		contract := c.zkbnbContract
+		for _, address := range info.ContractAddresses {
+			if contract == "" && strings.EqualFold(address.Name, zkbnbContractName) {
+				contract = address.Address
+			}
+		}
+		if !common.IsHexAddress(contract) {
+			return nil, errors.New("negotiate the l1 sign mode: the address of the ZkBNB contract is unknown")
+		}
+		signMode = &l1SignMode{mode: signer.L1SignModeTypedData, domain: txutils.TypedDataDomain(c.chainId, contract)}
+	}
+	c.signMode = signMode
+	return signMode, nil
+}
+
+func (c *l2Client) GenerateTypedData(ctx context.Context, txData interface{}, ops *types.TransactOpts) (*apitypes.TypedData, error) {
+	signMode, err := c.negotiateL1SignMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if signMode.mode != signer.L1SignModeTypedData {
+		return nil, fmt.Errorf("the l1 sign mode is %s", signMode.mode)
+	}
+	var txInfo txtypes.TxInfo
+	if nft, ok := txData.(*types.UpdateNftReq); ok {
+		txInfo, err = c.constructUpdateNFTTransaction(ctx, nft, ops)
+	} else {
+		txInfo, err = c.constructTransaction(ctx, txData, ops)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txutils.BuildTypedData(txInfo, signMode.domain)
+}
+
+// l1SignModeKey is the context key of the l1 sign mode set by WithL1SignMode.
+type l1SignModeKey struct{}
+
+// WithL1SignMode returns a context telling the client which scheme the L1 signatures of the
+// txs sent with it are made with. It is meant for txs signed outside the client, i.e. raw txs
+// passed to SendRawTx and signatures passed in the signatureList of the tx methods, which are
+// taken as personal signatures otherwise. The L1 signatures made by the client itself always
+// follow the negotiated sign mode.
+func WithL1SignMode(ctx context.Context, mode signer.L1SignMode) context.Context {
+	return context.WithValue(ctx, l1SignModeKey{}, mode)
+}
+
+func l1SignModeFromContext(ctx context.Context) signer.L1SignMode {
+	if mode, ok := ctx.Value(l1SignModeKey{}).(signer.L1SignMode); ok && mode != "" {
+		return mode
+	}
+	return signer.L1SignModePersonal
+}
+
+// signL1 signs the tx with the L1 signer according to the negotiated sign mode and returns
+// the sign mode of the signature.
+func (c *l2Client) signL1(ctx context.Context, txInfo txtypes.TxInfo) (string, signer.L1SignMode, error) {
+	signMode, err := c.negotiateL1SignMode(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if signMode.mode != signer.L1SignModeTypedData {
+		sig, err := c.l1Signer.Sign(txInfo.GetL1SignatureBody())
+		return sig, signer.L1SignModePersonal, err
+	}
+	typedDataSigner, ok := c.l1Signer.(signer.TypedDataSigner)
+	if !ok {
+		return "", "", fmt.Errorf("the l1 signer %T can't sign typed data", c.l1Signer)
+	}
+	typedData, err := txutils.BuildTypedData(txInfo, signMode.domain)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := typedDataSigner.SignTypedData(*typedData)
+	return sig, signer.L1SignModeTypedData, err
+}
+
+// signModeForm adds the l1 sign mode of the context to the form of a tx, nothing is added in
+// the personal sign mode so that servers without typed data support accept the txs.
+func signModeForm(ctx context.Context, form map[string][]string) {
+	if mode := l1SignModeFromContext(ctx); mode != signer.L1SignModePersonal {
+		form["l1_sign_mode"] = []string{string(mode)}
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// TxStage is a stage of the tx lifecycle, from being executed by ZkBNB to being verified on L1.
+type TxStage int
+
+const (
+	// TxStageExecuted is reached once the tx was executed by ZkBNB
+	TxStageExecuted TxStage = iota + 1
+	// TxStagePacked is reached once the tx was packed into a block
+	TxStagePacked
+	// TxStageCommitted is reached once the block of the tx was committed on L1
+	TxStageCommitted
+	// TxStageVerified is reached once the block of the tx was verified on L1
+	TxStageVerified
+)
+
+var (
+	// ErrTxFailed is returned by WaitForTx when ZkBNB marked the tx as failed
+	ErrTxFailed = errors.New("tx failed")
+	// ErrTxExpired is returned by WaitForTx when the tx was not executed before its ExpiredAt
+	ErrTxExpired = errors.New("tx expired")
+)
+
+const (
+	defaultWaitPollInterval    = 500 * time.Millisecond
+	defaultWaitMaxPollInterval = 5 * time.Second
+)
+
+type waitTxOption struct {
+	timeout         time.Duration
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+}
+
+type WaitTxOptionFunc func(*waitTxOption)
+
+// WaitTxWithTimeout bounds the time WaitForTx waits, on top of the context deadline.
+func WaitTxWithTimeout(timeout time.Duration) WaitTxOptionFunc {
+	return func(o *waitTxOption) {
+		o.timeout = timeout
+	}
+}
+
+// WaitTxWithPollInterval sets the first and the maximum delay between two polls, the delay
+// grows by 50% after each poll. It is 500ms up to 5s by default.
+func WaitTxWithPollInterval(interval, maxInterval time.Duration) WaitTxOptionFunc {
+	return func(o *waitTxOption) {
+		o.pollInterval = interval
+		o.maxPollInterval = maxInterval
+	}
+}
+
+// ZkBNBTxTracker follows a tx through its lifecycle.
+type ZkBNBTxTracker interface {
+	// WaitForTx polls the tx until it reached the given stage. It returns the tx and, once the
+	// tx was packed, its block. ErrTxFailed and ErrTxExpired are returned when the tx will never
+	// reach the stage. On timeout the last known state of the tx is returned with the error.
+	WaitForTx(ctx context.Context, hash string, stage TxStage, options ...WaitTxOptionFunc) (*types.EnrichedTx, *types.Block, error)
+}
+
+func (c *l2Client) WaitForTx(ctx context.Context, hash string, stage TxStage, options ...WaitTxOptionFunc) (*types.EnrichedTx, *types.Block, error) {
+	opt := &waitTxOption{
+		pollInterval:    defaultWaitPollInterval,
+		maxPollInterval: defaultWaitMaxPollInterval,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	if opt.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opt.timeout)
+		defer cancel()
+	}
+
+	var tx *types.EnrichedTx
+	interval := opt.pollInterval
+	for {
+		latest, err := c.GetTxWithContext(ctx, hash)
+		switch {
+		case err == nil:
+			tx = latest
+			if tx.Status == types.TxStatusFailed {
+				return tx, nil, fmt.Errorf("tx %s: %w", hash, ErrTxFailed)
+			}
+			if tx.Status == types.TxStatusPending && tx.ExpiredAt > 0 && time.Now().UnixMilli() > tx.ExpiredAt {
+				return tx, nil, fmt.Errorf("tx %s: %w", hash, ErrTxExpired)
+			}
+			if txReachedStage(tx, stage) {
+				if tx.BlockHeight <= 0 {
+					return tx, nil, nil
+				}
+				block, err := c.GetBlockByHeightWithContext(ctx, tx.BlockHeight)
+				if err != nil {
+					return tx, nil, err
+				}
+				return tx, block, nil
+			}
+		case ctx.Err() != nil:
+		case isRejected(err) || IsRetryable(err):
+			// the tx may not be visible yet, or the server is temporarily unavailable
+		default:
+			return tx, nil, err
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return tx, nil, fmt.Errorf("wait for tx %s: %w", hash, err)
+		}
+		interval = interval * 3 / 2
+		if interval > opt.maxPollInterval {
+			interval = opt.maxPollInterval
+		}
+	}
+}
+
+func txReachedStage(tx *types.EnrichedTx, stage TxStage) bool {
+	switch stage {
+	case TxStageExecuted:
+		return tx.Status >= types.TxStatusExecuted
+	case TxStagePacked:
+		return tx.Status >= types.TxStatusPacked && tx.BlockHeight > 0
+	case TxStageCommitted:
+		return tx.Status >= types.TxStatusCommitted || tx.CommittedAt > 0
+	case TxStageVerified:
+		return tx.Status >= types.TxStatusVerified || tx.VerifiedAt > 0
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForTx(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tx":
+			switch n := atomic.AddInt32(&polls, 1); {
+			case n == 1:
+				_, _ = w.Write([]byte(`{"code":24000,"message":"tx not found"}`))
+			case n == 2:
+				w.WriteHeader(http.StatusBadGateway)
+			case n < 5:
+				_, _ = fmt.Fprintf(w, `{"code":100,"hash":"abc","status":%d}`, types.TxStatusExecuted)
+			default:
+				_, _ = fmt.Fprintf(w, `{"code":100,"hash":"abc","status":%d,"block_height":7,"committed_at":1}`, types.TxStatusCommitted)
+			}
+		case "/api/v1/block":
+			_, _ = w.Write([]byte(`{"code":100,"height":7}`))
+		}
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	tx, block, err := sdkClient.WaitForTx(context.Background(), "abc", TxStageCommitted,
+		WaitTxWithPollInterval(time.Millisecond, 5*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), tx.BlockHeight)
+	assert.Equal(t, int64(7), block.Height)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&polls))
+
+	// the tx never gets verified
+	tx, _, err = sdkClient.WaitForTx(context.Background(), "abc", TxStageVerified,
+		WaitTxWithPollInterval(time.Millisecond, 5*time.Millisecond), WaitTxWithTimeout(50*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "abc", tx.Hash)
+}
+
+func TestWaitForTxFailure(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainNetworkId)
+	assert.NoError(t, err)
+
+	body.Store(fmt.Sprintf(`{"code":100,"hash":"abc","status":%d}`, types.TxStatusFailed))
+	_, _, err = sdkClient.WaitForTx(context.Background(), "abc", TxStageExecuted)
+	assert.ErrorIs(t, err, ErrTxFailed)
+
+	expiredAt := time.Now().Add(-time.Minute).UnixMilli()
+	body.Store(fmt.Sprintf(`{"code":100,"hash":"abc","status":%d,"expire_at":%d}`, types.TxStatusPending, expiredAt))
+	_, _, err = sdkClient.WaitForTx(context.Background(), "abc", TxStageExecuted)
+	assert.ErrorIs(t, err, ErrTxExpired)
+
+	// the lookups rejected by ZkBNB are polled until the timeout
+	body.Store(`{"code":20001,"message":"invalid param"}`)
+	_, _, err = sdkClient.WaitForTx(context.Background(), "abc", TxStageExecuted, WaitTxWithTimeout(50*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	body.Store(`{"code":100,"hash":`)
+	_, _, err = sdkClient.WaitForTx(context.Background(), "abc", TxStageExecuted)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+)
+
+// Wallet builds clients for the accounts of an HD wallet, all talking to the same endpoint
+// with the same options.
+type Wallet struct {
+	*accounts.HDWallet
+	url     string
+	options []ClientOptionFunc
+}
+
+// NewWallet creates a wallet from a BIP-39 mnemonic and its optional passphrase, the accounts
+// are derived under accounts.DefaultBaseDerivationPath.
+func NewWallet(url, mnemonic, passphrase string, chainId uint64, options ...ClientOptionFunc) (*Wallet, error) {
+	hdWallet, err := accounts.NewHDWallet(mnemonic, passphrase, chainId)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletWithHDWallet(url, hdWallet, options...), nil
+}
+
+// NewWalletWithHDWallet creates a wallet from an HD wallet, e.g. one with another derivation path.
+func NewWalletWithHDWallet(url string, hdWallet *accounts.HDWallet, options ...ClientOptionFunc) *Wallet {
+	return &Wallet{
+		HDWallet: hdWallet,
+		url:      url,
+		options:  options,
+	}
+}
+
+// Client returns a new client signing with the account of the given index.
+func (w *Wallet) Client(index uint32) (ZkBNBClient, error) {
+	account, err := w.Account(index)
+	if err != nil {
+		return nil, err
+	}
+	return NewZkBNBClientWithHDAccount(w.url, account, w.options...)
+}
+
+// NewZkBNBClientWithHDAccount creates a client signing with the keys of an HD wallet account.
+func NewZkBNBClientWithHDAccount(url string, account *accounts.HDAccount, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	if account == nil {
+		return nil, errors.New("hd account must be set")
+	}
+	opt := newClientOption(options)
+	client := &l2Client{
+		endpoint:   url,
+		privateKey: account.PrivateKey(),
+		address:    account.Address,
+		chainId:    account.ChainId,
+		l1Signer:   account.L1Signer(),
+		keyManager: account.KeyManager(),
+	}
+	opt.apply(client)
+	return client, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet(t *testing.T) {
+	wallet, err := NewWallet(testEndpoint, "test test test test test test test test test test test junk", "", 97)
+	assert.NoError(t, err)
+	for index := uint32(0); index < 3; index++ {
+		account, err := wallet.Account(index)
+		assert.NoError(t, err)
+		client, err := wallet.Client(index)
+		assert.NoError(t, err)
+		l2 := client.(*l2Client)
+		assert.Equal(t, account.Address, l2.address)
+		assert.Equal(t, uint64(97), l2.chainId)
+		assert.Equal(t, account.KeyManager().PubKey().Bytes(), client.KeyManager().PubKey().Bytes())
+	}
+	_, err = NewWallet(testEndpoint, "not a mnemonic", "", 97)
+	assert.Error(t, err)
+	_, err = NewZkBNBClientWithHDAccount(testEndpoint, nil)
+	assert.Error(t, err)
+}
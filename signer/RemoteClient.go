@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRemoteSignerTimeout is the timeout of a request to a remote signer unless changed
+// with WithRemoteSignerTimeout.
+const DefaultRemoteSignerTimeout = 10 * time.Second
+
+type remoteSignerOption struct {
+	timeout    time.Duration
+	httpClient *http.Client
+	headers    http.Header
+}
+
+type RemoteSignerOptionFunc func(*remoteSignerOption)
+
+// WithRemoteSignerTimeout sets the timeout of every request to the remote signer.
+func WithRemoteSignerTimeout(timeout time.Duration) RemoteSignerOptionFunc {
+	return func(o *remoteSignerOption) {
+		o.timeout = timeout
+	}
+}
+
+// WithRemoteSignerBearerToken authenticates the requests with an "Authorization: Bearer" header.
+func WithRemoteSignerBearerToken(token string) RemoteSignerOptionFunc {
+	return WithRemoteSignerHeader("Authorization", "Bearer "+token)
+}
+
+// WithRemoteSignerHeader adds a header to every request, e.g. an api key.
+func WithRemoteSignerHeader(key, value string) RemoteSignerOptionFunc {
+	return func(o *remoteSignerOption) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithRemoteSignerHTTPClient sets the http client used to reach the remote signer, e.g. one
+// configured with client certificates.
+func WithRemoteSignerHTTPClient(httpClient *http.Client) RemoteSignerOptionFunc {
+	return func(o *remoteSignerOption) {
+		o.httpClient = httpClient
+	}
+}
+
+// RemoteClient is the JSON-RPC over http client of the remote signers.
+type RemoteClient struct {
+	// id is accessed atomically, it comes first to be 64-bit aligned
+	id     uint64
+	url    string
+	option *remoteSignerOption
+}
+
+// NewRemoteClient creates a client of the JSON-RPC endpoint url.
+func NewRemoteClient(url string, options ...RemoteSignerOptionFunc) *RemoteClient {
+	option := &remoteSignerOption{
+		timeout:    DefaultRemoteSignerTimeout,
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+	return &RemoteClient{url: url, option: option}
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Call calls the JSON-RPC method and decodes its result into result, the call is aborted
+// when the context is done or the timeout of the client elapsed.
+func (c *RemoteClient) Call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.option.timeout)
+	defer cancel()
+
+	request, err := json.Marshal(&jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(request))
+	if err != nil {
+		return err
+	}
+	for key, values := range c.option.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.option.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("remote signer: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	response := &jsonRPCResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("remote signer: invalid response: %v", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("remote signer: %s (code %d)", response.Error.Message, response.Error.Code)
+	}
+	if len(response.Result) == 0 {
+		return errors.New("remote signer: empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
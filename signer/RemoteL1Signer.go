@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	accounts2 "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RemoteSignMethod is the JSON-RPC method called to sign a body. Like eth_sign, its params
+// are the address of the signer and the hex encoded body, and its result is the hex encoded
+// 65 bytes signature of the EIP-191 personal message hash of the body.
+const RemoteSignMethod = "eth_sign"
+
+// RemoteL1Signer is an L1Signer delegating the signatures to an external signing service over
+// JSON-RPC, e.g. Clef or Web3Signer, so that the L1 key never enters the process. Every
+// signature is checked to be made by the expected address.
+type RemoteL1Signer struct {
+	client  *RemoteClient
+	address common.Address
+
+	mu     sync.Mutex
+	pubKey string
+}
+
+// NewRemoteL1Signer creates a signer for the given address calling the JSON-RPC endpoint url.
+func NewRemoteL1Signer(url, address string, options ...RemoteSignerOptionFunc) (*RemoteL1Signer, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid l1 address %s", address)
+	}
+	return &RemoteL1Signer{
+		client:  NewRemoteClient(url, options...),
+		address: common.HexToAddress(address),
+	}, nil
+}
+
+func (signer *RemoteL1Signer) Sign(body string) (string, error) {
+	return signer.SignWithContext(context.Background(), body)
+}
+
+// SignWithContext signs the body, the request is aborted when the context is done.
+func (signer *RemoteL1Signer) SignWithContext(ctx context.Context, body string) (string, error) {
+	var signature string
+	if err := signer.client.Call(ctx, RemoteSignMethod, &signature, signer.address.Hex(), hexutil.Encode([]byte(body))); err != nil {
+		return "", err
+	}
+	return signer.checkSignature(accounts2.TextHash([]byte(body)), signature)
+}
+
+// checkSignature normalizes the recovery id of the signature of hash and checks that it was
+// made by the address of the signer.
+func (signer *RemoteL1Signer) checkSignature(hash []byte, signature string) (string, error) {
+	signatureBytes, err := hexutil.Decode(signature)
+	if err != nil || len(signatureBytes) != 65 {
+		return "", fmt.Errorf("invalid signature %s from the remote signer", signature)
+	}
+	// some signers return a recovery id of 0 or 1 instead of 27 or 28
+	if signatureBytes[64] < 27 {
+		signatureBytes[64] += 27
+	}
+
+	recoverSignature := common.CopyBytes(signatureBytes)
+	recoverSignature[64] -= 27
+	pubKey, err := crypto.SigToPub(hash, recoverSignature)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature from the remote signer: %v", err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != signer.address {
+		return "", fmt.Errorf("the remote signer signed with %s instead of %s", crypto.PubkeyToAddress(*pubKey).Hex(), signer.address.Hex())
+	}
+	signer.mu.Lock()
+	signer.pubKey = hexutil.Encode(crypto.FromECDSAPub(pubKey))
+	signer.mu.Unlock()
+	return hexutil.Encode(signatureBytes), nil
+}
+
+// GetPublicKey returns the public key recovered from the last signature, the remote signer
+// only exposes addresses so it is empty until the first signature.
+func (signer *RemoteL1Signer) GetPublicKey() string {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	return signer.pubKey
+}
+
+func (signer *RemoteL1Signer) GetAddress() string {
+	return signer.address.Hex()
+}
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// L1SignMode is the scheme of the L1 signatures of the l2 txs.
+type L1SignMode string
+
+const (
+	// L1SignModePersonal signs the human readable body of GetL1SignatureBody as a personal
+	// message, it is supported by every ZkBNB server.
+	L1SignModePersonal L1SignMode = "personal_sign"
+	// L1SignModeTypedData signs the EIP-712 typed data of the tx, so that wallets show its
+	// fields. It is experimental, see client.WithExperimentalTypedDataSigning.
+	L1SignModeTypedData L1SignMode = "eip712"
+)
+
+// RemoteSignTypedDataMethod is the JSON-RPC method called to sign EIP-712 typed data. Its
+// params are the address of the signer and the typed data, and its result is the hex encoded
+// 65 bytes signature.
+const RemoteSignTypedDataMethod = "eth_signTypedData_v4"
+
+// TypedDataSigner is implemented by the L1 signers able to sign EIP-712 typed data. The
+// signature has a recovery id of 27 or 28 like the ones of L1Signer.Sign.
+type TypedDataSigner interface {
+	SignTypedData(typedData apitypes.TypedData) (string, error)
+}
+
+func (singer *DefaultL1Singer) SignTypedData(typedData apitypes.TypedData) (string, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", err
+	}
+	signatureBytes, err := crypto.Sign(hash, singer.privateKey)
+	if err != nil {
+		return "", err
+	}
+	signatureBytes[64] += 27
+	return hexutil.Encode(signatureBytes), nil
+}
+
+func (signer *RemoteL1Signer) SignTypedData(typedData apitypes.TypedData) (string, error) {
+	return signer.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext signs the typed data, the request is aborted when the context is done.
+func (signer *RemoteL1Signer) SignTypedDataWithContext(ctx context.Context, typedData apitypes.TypedData) (string, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", err
+	}
+	var signature string
+	if err := signer.client.Call(ctx, RemoteSignTypedDataMethod, &signature, signer.address.Hex(), typedData); err != nil {
+		return "", err
+	}
+	return signer.checkSignature(hash, signature)
+}
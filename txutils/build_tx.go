@@ -0,0 +1,203 @@
+package txutils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// ErrIncompleteTx is returned by BuildTx when a field needed to build the tx is not set.
+var ErrIncompleteTx = errors.New("incomplete tx")
+
+// ComputeCallDataHash computes the CallDataHash of TransactOpts for the given call data.
+func ComputeCallDataHash(callData string) []byte {
+	hFunc := mimc.NewMiMC()
+	var x fr.Element
+	_ = x.SetBytes([]byte(callData))
+	b := x.Bytes()
+	hFunc.Write(b[:])
+	return hFunc.Sum(nil)
+}
+
+// BuildTx builds and signs an l2 tx without any api access, e.g. on a machine with no
+// network. It returns the tx type and tx info to be sent with SendRawTx.
+//
+// tx is one of the requests taken by the tx methods of the client: *types.ChangePubKeyReq,
+// *types.TransferTxReq, *types.WithdrawTxReq, *types.CreateCollectionTxReq,
+// *types.MintNftTxReq, *types.TransferNftTxReq, *types.WithdrawNftTxReq,
+// *types.CancelOfferTxReq or *types.AtomicMatchTxReq. Unlike the client, BuildTx does not
+// fill the TransactOpts: FromAccountIndex, GasAccountIndex, GasFeeAssetAmount and ExpiredAt
+// must be set, Nonce is taken as is and CallDataHash is only computed from CallData. The
+// l1Signer signs the L1 signature of every tx but AtomicMatch, for which it may be nil, as a
+// personal signature: typed data signatures are made with Envelope.SignL1TypedData.
+// Both signatures are verified against the keys of the signers before the tx is returned.
+func BuildTx(key accounts.KeyManager, l1Signer signer.L1Signer, tx interface{}, ops *types.TransactOpts) (uint32, string, error) {
+	if key == nil {
+		return 0, "", fmt.Errorf("%w: key manager is nil", ErrIncompleteTx)
+	}
+	envelope, err := NewEnvelope(tx, ops)
+	if err != nil {
+		return 0, "", err
+	}
+	if err := envelope.SignL2(key); err != nil {
+		return 0, "", err
+	}
+	l1Address := ""
+	if envelope.L1SignBody != "" {
+		if l1Signer == nil {
+			return 0, "", fmt.Errorf("%w: l1 signer is nil", ErrIncompleteTx)
+		}
+		if err := envelope.SignL1(l1Signer); err != nil {
+			return 0, "", err
+		}
+		l1Address = l1Signer.GetAddress()
+	}
+	return envelope.Finalize(common.Bytes2Hex(key.PubKey().Bytes()), l1Address)
+}
+
+// convertTx validates the request and the opts and converts them to an unsigned tx.
+func convertTx(tx interface{}, ops *types.TransactOpts) (txtypes.TxInfo, error) {
+	if ops == nil {
+		return nil, fmt.Errorf("%w: transact opts are nil", ErrIncompleteTx)
+	}
+	// the opts are copied, the client reuses them between calls but they must not change here
+	opts := *ops
+	if err := validateTransactOpts(&opts); err != nil {
+		return nil, err
+	}
+
+	var txInfo txtypes.TxInfo
+	switch tx := tx.(type) {
+	case *types.ChangePubKeyReq:
+		if err := validateAddress("l1 address", tx.L1Address); err != nil {
+			return nil, err
+		}
+		txInfo = ConvertChangePubKeyTxInfo(tx, &opts)
+	case *types.TransferTxReq:
+		if err := validateAddress("to", tx.To); err != nil {
+			return nil, err
+		}
+		if tx.AssetAmount == nil {
+			return nil, fmt.Errorf("%w: asset amount is not set", ErrIncompleteTx)
+		}
+		opts.ToAccountAddress = tx.To
+		txInfo = ConvertTransferTx(tx, &opts)
+	case *types.WithdrawTxReq:
+		if err := validateAddress("to address", tx.ToAddress); err != nil {
+			return nil, err
+		}
+		if tx.AssetAmount == nil {
+			return nil, fmt.Errorf("%w: asset amount is not set", ErrIncompleteTx)
+		}
+		txInfo = ConvertWithdrawTx(tx, &opts)
+	case *types.CreateCollectionTxReq:
+		txInfo = ConvertCreateCollectionTxInfo(tx, &opts)
+	case *types.MintNftTxReq:
+		if err := validateAddress("to", tx.To); err != nil {
+			return nil, err
+		}
+		opts.ToAccountAddress = tx.To
+		txInfo = ConvertMintNftTxInfo(tx, &opts)
+	case *types.TransferNftTxReq:
+		if err := validateAddress("to", tx.To); err != nil {
+			return nil, err
+		}
+		opts.ToAccountAddress = tx.To
+		txInfo = ConvertTransferNftTxInfo(tx, &opts)
+	case *types.WithdrawNftTxReq:
+		if err := validateAddress("to address", tx.ToAddress); err != nil {
+			return nil, err
+		}
+		if tx.AccountIndex != opts.FromAccountIndex {
+			return nil, fmt.Errorf("account index %d does not match the from account index %d", tx.AccountIndex, opts.FromAccountIndex)
+		}
+		txInfo = ConvertWithdrawNftTxInfo(tx, &opts)
+	case *types.CancelOfferTxReq:
+		txInfo = ConvertCancelOfferTxInfo(tx, &opts)
+	case *types.AtomicMatchTxReq:
+		if tx.BuyOffer == nil || tx.SellOffer == nil {
+			return nil, fmt.Errorf("%w: buy and sell offers must be set", ErrIncompleteTx)
+		}
+		txInfo = ConvertAtomicMatchTxInfo(tx, &opts)
+	default:
+		return nil, fmt.Errorf("unsupported tx %T", tx)
+	}
+	if err := txInfo.Validate(); err != nil {
+		return nil, err
+	}
+	return txInfo, nil
+}
+
+func validateTransactOpts(ops *types.TransactOpts) error {
+	if ops.FromAccountIndex <= 0 {
+		return fmt.Errorf("%w: from account index is not set", ErrIncompleteTx)
+	}
+	if ops.GasAccountIndex <= 0 {
+		return fmt.Errorf("%w: gas account index is not set", ErrIncompleteTx)
+	}
+	if ops.GasFeeAssetId < 0 {
+		return fmt.Errorf("invalid gas fee asset id %d", ops.GasFeeAssetId)
+	}
+	if ops.GasFeeAssetAmount == nil {
+		return fmt.Errorf("%w: gas fee asset amount is not set", ErrIncompleteTx)
+	}
+	if ops.GasFeeAssetAmount.Sign() < 0 {
+		return fmt.Errorf("invalid gas fee asset amount %s", ops.GasFeeAssetAmount)
+	}
+	if ops.ExpiredAt == 0 {
+		return fmt.Errorf("%w: expired at is not set", ErrIncompleteTx)
+	}
+	if ops.ExpiredAt <= time.Now().UnixMilli() {
+		return fmt.Errorf("the tx expired at %d", ops.ExpiredAt)
+	}
+	if ops.Nonce < 0 {
+		return fmt.Errorf("invalid nonce %d", ops.Nonce)
+	}
+	if len(ops.CallDataHash) == 0 {
+		ops.CallDataHash = ComputeCallDataHash(ops.CallData)
+	}
+	return nil
+}
+
+func validateAddress(field, address string) error {
+	if address == "" {
+		return fmt.Errorf("%w: %s is not set", ErrIncompleteTx, field)
+	}
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid %s address %s", field, address)
+	}
+	return nil
+}
+
+// setSigs sets the EdDSA and the L1 signature of the tx.
+func setSigs(tx txtypes.TxInfo, sig []byte, l1Sig string) {
+	switch tx := tx.(type) {
+	case *txtypes.ChangePubKeyInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.TransferTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.WithdrawTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.CreateCollectionTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.MintNftTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.TransferNftTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.CancelOfferTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.WithdrawNftTxInfo:
+		tx.Sig, tx.L1Sig = sig, l1Sig
+	case *txtypes.AtomicMatchTxInfo:
+		tx.Sig = sig
+	}
+}
@@ -0,0 +1,79 @@
+package txutils
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestBuildTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	pubKey := common.Bytes2Hex(keyManager.PubKey().Bytes())
+
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             0,
+	}
+	to := "0x000000000000000000000000000000000000dEaD"
+	txType, txInfo, err := BuildTx(keyManager, l1Signer, &types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)}, ops)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(types.TxTypeTransfer), txType)
+	assert.NoError(t, VerifyTxSig(txType, txInfo, pubKey))
+	assert.NoError(t, VerifyTxL1Sig(txType, txInfo, l1Signer.GetAddress()))
+	tx, err := ParseTxInfo(txType, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), tx.GetFromAccountIndex())
+	// the opts of the caller are left untouched
+	assert.Zero(t, ops.TxType)
+	assert.Empty(t, ops.CallDataHash)
+
+	pubKeyPoint := keyManager.PubKeyPoint()
+	txType, txInfo, err = BuildTx(keyManager, l1Signer, &types.ChangePubKeyReq{L1Address: l1Signer.GetAddress(), PubKeyX: pubKeyPoint[0], PubKeyY: pubKeyPoint[1]}, ops)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTxL1Sig(txType, txInfo, l1Signer.GetAddress()))
+
+	// every required field is checked
+	incomplete := []func(ops *types.TransactOpts){
+		func(ops *types.TransactOpts) { ops.FromAccountIndex = 0 },
+		func(ops *types.TransactOpts) { ops.GasAccountIndex = 0 },
+		func(ops *types.TransactOpts) { ops.GasFeeAssetAmount = nil },
+		func(ops *types.TransactOpts) { ops.ExpiredAt = 0 },
+	}
+	for _, unset := range incomplete {
+		opts := *ops
+		unset(&opts)
+		_, _, err = BuildTx(keyManager, l1Signer, &types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)}, &opts)
+		assert.ErrorIs(t, err, ErrIncompleteTx)
+	}
+	_, _, err = BuildTx(keyManager, l1Signer, &types.TransferTxReq{AssetAmount: big.NewInt(100)}, ops)
+	assert.ErrorIs(t, err, ErrIncompleteTx)
+	_, _, err = BuildTx(keyManager, nil, &types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)}, ops)
+	assert.ErrorIs(t, err, ErrIncompleteTx)
+
+	expired := *ops
+	expired.ExpiredAt = time.Now().Add(-time.Minute).UnixMilli()
+	_, _, err = BuildTx(keyManager, l1Signer, &types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)}, &expired)
+	assert.Error(t, err)
+	_, _, err = BuildTx(keyManager, l1Signer, &types.ChangePubKeyReq{L1Address: to}, ops)
+	assert.Error(t, err)
+	_, _, err = BuildTx(keyManager, l1Signer, &types.UpdateNftReq{}, ops)
+	assert.Error(t, err)
+}
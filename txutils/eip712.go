@@ -0,0 +1,231 @@
+package txutils
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+	// TypedDataDomainName and TypedDataDomainVersion are the name and the version of the
+	// EIP-712 domain of the l2 txs.
+	TypedDataDomainName    = "ZkBNB"
+	TypedDataDomainVersion = "1"
+)
+
+// TypedDataDomain returns the EIP-712 domain of the l2 txs, separated by the L1 chain id and
+// the address of the ZkBNB contract.
+func TypedDataDomain(chainId uint64, zkbnbContract string) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              TypedDataDomainName,
+		Version:           TypedDataDomainVersion,
+		ChainId:           math.NewHexOrDecimal256(int64(chainId)),
+		VerifyingContract: common.HexToAddress(zkbnbContract).Hex(),
+	}
+}
+
+type typedField struct {
+	name  string
+	typ   string
+	value interface{}
+}
+
+// BuildTypedData returns the EIP-712 typed data signed in place of GetL1SignatureBody in the
+// experimental typed data sign mode. Its message holds the fields of the tx covered by the L1
+// signature, the primary type is named after the tx type.
+func BuildTypedData(tx txtypes.TxInfo, domain apitypes.TypedDataDomain) (*apitypes.TypedData, error) {
+	primaryType, fields, err := typedFields(tx)
+	if err != nil {
+		return nil, err
+	}
+	typedData := &apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+		},
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     make(apitypes.TypedDataMessage, len(fields)),
+	}
+	for _, field := range fields {
+		typedData.Types[primaryType] = append(typedData.Types[primaryType], apitypes.Type{Name: field.name, Type: field.typ})
+		typedData.Message[field.name] = field.value
+	}
+	return typedData, nil
+}
+
+// TxTypedData returns the EIP-712 typed data of the tx info.
+func TxTypedData(txType uint32, txInfo string, domain apitypes.TypedDataDomain) (*apitypes.TypedData, error) {
+	tx, err := parseL1SignedTx(txType, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTypedData(tx, domain)
+}
+
+// TypedDataHash returns the hash signed for the typed data.
+func TypedDataHash(typedData *apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(*typedData)
+	return hash, err
+}
+
+// VerifyTxL1TypedDataSig verifies the L1 signature of the tx info against l1Address, like
+// VerifyTxL1Sig does for txs signed in the typed data sign mode.
+func VerifyTxL1TypedDataSig(txType uint32, txInfo string, l1Address string, domain apitypes.TypedDataDomain) error {
+	tx, err := parseL1SignedTx(txType, txInfo)
+	if err != nil {
+		return err
+	}
+	return VerifyL1TypedDataSig(tx, l1Address, domain)
+}
+
+// VerifyL1TypedDataSig verifies that the L1Sig of the tx was made by l1Address over the
+// typed data returned by BuildTypedData.
+func VerifyL1TypedDataSig(tx txtypes.TxInfo, l1Address string, domain apitypes.TypedDataDomain) error {
+	if !common.IsHexAddress(l1Address) {
+		return fmt.Errorf("invalid l1 address %s", l1Address)
+	}
+	typedData, err := BuildTypedData(tx, domain)
+	if err != nil {
+		return err
+	}
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return err
+	}
+	l1Sig, err := hexutil.Decode(l1SigOf(tx))
+	if err != nil || len(l1Sig) != crypto.SignatureLength || l1Sig[64] < 27 {
+		return fmt.Errorf("invalid l1 signature")
+	}
+	l1Sig[64] -= 27
+	pubKey, err := crypto.SigToPub(hash, l1Sig)
+	if err != nil || crypto.PubkeyToAddress(*pubKey) != common.HexToAddress(l1Address) {
+		return fmt.Errorf("invalid l1 signature")
+	}
+	return nil
+}
+
+func typedFields(tx txtypes.TxInfo) (string, []typedField, error) {
+	switch tx := tx.(type) {
+	case *txtypes.ChangePubKeyInfo:
+		return "ChangePubKey", append([]typedField{
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"l1Address", "address", tx.L1Address},
+			{"pubKeyX", "bytes32", hexutil.Encode(common.LeftPadBytes(tx.PubKeyX, 32))},
+			{"pubKeyY", "bytes32", hexutil.Encode(common.LeftPadBytes(tx.PubKeyY, 32))},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.TransferTxInfo:
+		return "Transfer", append([]typedField{
+			{"fromAccountIndex", "uint64", typedInt(tx.FromAccountIndex)},
+			{"toAddress", "address", tx.ToL1Address},
+			{"assetId", "uint64", typedInt(tx.AssetId)},
+			{"assetName", "string", tx.AssetName},
+			{"assetAmount", "uint256", typedBigInt(tx.AssetAmount)},
+			{"callDataHash", "bytes", hexutil.Encode(tx.CallDataHash)},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.WithdrawTxInfo:
+		return "Withdraw", append([]typedField{
+			{"fromAccountIndex", "uint64", typedInt(tx.FromAccountIndex)},
+			{"toAddress", "address", tx.ToAddress},
+			{"assetId", "uint64", typedInt(tx.AssetId)},
+			{"assetName", "string", tx.AssetName},
+			{"assetAmount", "uint256", typedBigInt(tx.AssetAmount)},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.CreateCollectionTxInfo:
+		return "CreateCollection", append([]typedField{
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"collectionId", "uint64", typedInt(tx.CollectionId)},
+			{"name", "string", tx.Name},
+			{"introduction", "string", tx.Introduction},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.MintNftTxInfo:
+		return "MintNft", append([]typedField{
+			{"creatorAccountIndex", "uint64", typedInt(tx.CreatorAccountIndex)},
+			{"toAddress", "address", tx.ToL1Address},
+			{"nftContentHash", "string", tx.NftContentHash},
+			{"nftCollectionId", "uint64", typedInt(tx.NftCollectionId)},
+			{"royaltyRate", "uint64", typedInt(tx.RoyaltyRate)},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.TransferNftTxInfo:
+		return "TransferNft", append([]typedField{
+			{"fromAccountIndex", "uint64", typedInt(tx.FromAccountIndex)},
+			{"toAddress", "address", tx.ToL1Address},
+			{"nftIndex", "uint64", typedInt(tx.NftIndex)},
+			{"nftName", "string", tx.NftName},
+			{"callDataHash", "bytes", hexutil.Encode(tx.CallDataHash)},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.WithdrawNftTxInfo:
+		return "WithdrawNft", append([]typedField{
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"toAddress", "address", tx.ToAddress},
+			{"nftIndex", "uint64", typedInt(tx.NftIndex)},
+			{"nftName", "string", tx.NftName},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.CancelOfferTxInfo:
+		return "CancelOffer", append([]typedField{
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"offerId", "uint64", typedInt(tx.OfferId)},
+			{"nftName", "string", tx.NftName},
+		}, gasFields(tx.GasAccountIndex, tx.GasFeeAssetId, tx.GasFeeAssetAmount, tx.Nonce, tx.ExpiredAt)...), nil
+	case *txtypes.OfferTxInfo:
+		return "Offer", []typedField{
+			{"type", "uint8", typedInt(tx.Type)},
+			{"offerId", "uint64", typedInt(tx.OfferId)},
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"nftIndex", "uint64", typedInt(tx.NftIndex)},
+			{"nftName", "string", tx.NftName},
+			{"assetId", "uint64", typedInt(tx.AssetId)},
+			{"assetName", "string", tx.AssetName},
+			{"assetAmount", "uint256", typedBigInt(tx.AssetAmount)},
+			{"listedAt", "uint64", typedInt(tx.ListedAt)},
+			{"expiredAt", "uint64", typedInt(tx.ExpiredAt)},
+			{"royaltyRate", "uint64", typedInt(tx.RoyaltyRate)},
+			{"channelAccountIndex", "uint64", typedInt(tx.ChannelAccountIndex)},
+			{"channelRate", "uint64", typedInt(tx.ChannelRate)},
+			{"protocolRate", "uint64", typedInt(tx.ProtocolRate)},
+		}, nil
+	case *txtypes.UpdateNFTTxInfo:
+		return "UpdateNft", []typedField{
+			{"accountIndex", "uint64", typedInt(tx.AccountIndex)},
+			{"nftIndex", "uint64", typedInt(tx.NftIndex)},
+			{"mutableAttributes", "string", tx.MutableAttributes},
+			{"nonce", "uint64", typedInt(tx.Nonce)},
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("l2 tx type %d has no l1 signature", tx.GetTxType())
+	}
+}
+
+func gasFields(gasAccountIndex, gasFeeAssetId int64, gasFeeAssetAmount *big.Int, nonce, expiredAt int64) []typedField {
+	return []typedField{
+		{"gasAccountIndex", "uint64", typedInt(gasAccountIndex)},
+		{"gasFeeAssetId", "uint64", typedInt(gasFeeAssetId)},
+		{"gasFeeAssetAmount", "uint256", typedBigInt(gasFeeAssetAmount)},
+		{"nonce", "uint64", typedInt(nonce)},
+		{"expiredAt", "uint64", typedInt(expiredAt)},
+	}
+}
+
+// typedInt and typedBigInt encode integers as decimal strings, which survive the JSON
+// encoding of the typed data sent to remote signers.
+func typedInt(x int64) string {
+	return strconv.FormatInt(x, 10)
+}
+
+func typedBigInt(x *big.Int) string {
+	if x == nil {
+		return "0"
+	}
+	return x.String()
+}
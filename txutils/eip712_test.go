@@ -0,0 +1,79 @@
+package txutils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestTypedData(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l1Signer, err := signer.NewL1Singer(common.Bytes2Hex(crypto.FromECDSA(key)))
+	assert.NoError(t, err)
+	typedDataSigner := l1Signer.(signer.TypedDataSigner)
+	contract := "0x000000000000000000000000000000000000bEEF"
+	domain := TypedDataDomain(97, contract)
+
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             5,
+	}
+	to := "0x000000000000000000000000000000000000dEaD"
+	txs := []interface{}{
+		&types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)},
+		&types.WithdrawTxReq{ToAddress: to, AssetAmount: big.NewInt(100)},
+		&types.CreateCollectionTxReq{Name: "collection"},
+		&types.MintNftTxReq{To: to, NftCollectionId: 1},
+		&types.TransferNftTxReq{To: to, NftIndex: 1},
+		&types.WithdrawNftTxReq{AccountIndex: 2, ToAddress: to, NftIndex: 1},
+		&types.CancelOfferTxReq{OfferId: 1},
+	}
+	for _, req := range txs {
+		tx, err := convertTx(req, ops)
+		assert.NoError(t, err, "%T", req)
+		typedData, err := BuildTypedData(tx, domain)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(97), (*big.Int)(typedData.Domain.ChainId).Int64())
+		l1Sig, err := typedDataSigner.SignTypedData(*typedData)
+		assert.NoError(t, err)
+		setSigs(tx, nil, l1Sig)
+		assert.NoError(t, VerifyL1TypedDataSig(tx, l1Signer.GetAddress(), domain), "%T", req)
+
+		// the signature is bound to the domain and is not a personal signature
+		assert.Error(t, VerifyL1TypedDataSig(tx, l1Signer.GetAddress(), TypedDataDomain(56, contract)))
+		assert.Error(t, VerifyL1TypedDataSig(tx, l1Signer.GetAddress(), TypedDataDomain(97, to)))
+		assert.Error(t, VerifyL1Sig(tx, l1Signer.GetAddress()))
+	}
+
+	// the typed data covers the fields of the tx
+	tx, err := convertTx(&types.TransferTxReq{To: to, AssetAmount: big.NewInt(100)}, ops)
+	assert.NoError(t, err)
+	typedData, err := BuildTypedData(tx, domain)
+	assert.NoError(t, err)
+	assert.Equal(t, "Transfer", typedData.PrimaryType)
+	assert.Equal(t, "100", typedData.Message["assetAmount"])
+	l1Sig, err := typedDataSigner.SignTypedData(*typedData)
+	assert.NoError(t, err)
+	transfer := tx.(*txtypes.TransferTxInfo)
+	transfer.L1Sig = l1Sig
+	txInfo, _ := json.Marshal(transfer)
+	assert.NoError(t, VerifyTxL1TypedDataSig(types.TxTypeTransfer, string(txInfo), l1Signer.GetAddress(), domain))
+	transfer.AssetAmount = big.NewInt(101)
+	txInfo, _ = json.Marshal(transfer)
+	assert.Error(t, VerifyTxL1TypedDataSig(types.TxTypeTransfer, string(txInfo), l1Signer.GetAddress(), domain))
+
+	_, err = BuildTypedData(&txtypes.AtomicMatchTxInfo{}, domain)
+	assert.Error(t, err)
+}
@@ -0,0 +1,413 @@
+package txutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// EnvelopeVersion is the version of the envelopes created by this package. Version 2 added the
+// sign mode of the L1 signature, envelopes of version 1 are still decoded and only hold
+// personal signatures.
+const EnvelopeVersion = 2
+
+// envelopeMagic starts the binary encoding of an envelope.
+var envelopeMagic = []byte("zkbe")
+
+var (
+	// ErrEnvelopeMismatch is returned when combining envelopes of different txs or with
+	// different signatures for the same slot.
+	ErrEnvelopeMismatch = errors.New("envelopes do not match")
+	// ErrEnvelopeNotSigned is returned by Finalize when a signature is missing.
+	ErrEnvelopeNotSigned = errors.New("envelope is not fully signed")
+)
+
+// Envelope carries an unsigned or partially signed l2 tx between the machines holding the
+// EdDSA key and the L1 key, like a PSBT does for bitcoin. It is created with NewEnvelope or
+// NewEnvelopeFromTxInfo, signed with SignL2 and SignL1 or SignL1TypedData, possibly on
+// different machines and in any order, merged with CombineEnvelopes and turned into the
+// SendRawTx input with Finalize. A typed data L1 signature has to be sent with the L1SignMode
+// of the envelope, see client.WithL1SignMode.
+//
+// Envelopes are encoded as JSON, which shows the L1 sign body to the signer, or with
+// MarshalBinary, which leaves it out since it is derived from the tx info.
+type Envelope struct {
+	Version uint8  `json:"version"`
+	TxType  uint32 `json:"tx_type"`
+	// TxInfo is the tx info without signatures
+	TxInfo string `json:"tx_info"`
+	// L1SignBody is the message signed by the L1 key, empty for txs without L1 signature
+	L1SignBody string `json:"l1_sign_body,omitempty"`
+	// L2Sig is the hex encoded EdDSA signature
+	L2Sig string `json:"l2_sig,omitempty"`
+	// L1Sig is the hex encoded L1 signature
+	L1Sig string `json:"l1_sig,omitempty"`
+	// L1SignMode is the scheme of L1Sig, empty for personal signatures
+	L1SignMode signer.L1SignMode `json:"l1_sign_mode,omitempty"`
+	// TypedDataDomain is the EIP-712 domain of L1Sig in the typed data sign mode
+	TypedDataDomain *apitypes.TypedDataDomain `json:"typed_data_domain,omitempty"`
+}
+
+// NewEnvelope creates an unsigned envelope from a tx request and fully specified opts, with
+// the same validation as BuildTx.
+func NewEnvelope(tx interface{}, ops *types.TransactOpts) (*Envelope, error) {
+	txInfo, err := convertTx(tx, ops)
+	if err != nil {
+		return nil, err
+	}
+	return newEnvelope(txInfo)
+}
+
+// NewEnvelopeFromTxInfo creates an unsigned envelope from the tx info of an l2 tx, e.g. one
+// constructed by the client. Signatures already present in the tx info are dropped.
+func NewEnvelopeFromTxInfo(txType uint32, txInfo string) (*Envelope, error) {
+	tx, err := ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, err
+	}
+	return newEnvelope(tx)
+}
+
+func newEnvelope(tx txtypes.TxInfo) (*Envelope, error) {
+	setSigs(tx, nil, "")
+	txInfo, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Version:    EnvelopeVersion,
+		TxType:     uint32(tx.GetTxType()),
+		TxInfo:     string(txInfo),
+		L1SignBody: tx.GetL1SignatureBody(),
+	}, nil
+}
+
+// tx parses the tx info of the envelope and checks that the L1 sign body matches it, so that
+// a signer never signs a body which does not belong to the tx.
+func (e *Envelope) tx() (txtypes.TxInfo, error) {
+	if e.Version != EnvelopeVersion && (e.Version != 1 || e.L1SignMode != "" || e.TypedDataDomain != nil) {
+		return nil, fmt.Errorf("unsupported envelope version %d", e.Version)
+	}
+	switch e.L1SignMode {
+	case "":
+		if e.TypedDataDomain != nil {
+			return nil, errors.New("typed data domain of a personal signature")
+		}
+	case signer.L1SignModeTypedData:
+		if e.TypedDataDomain == nil {
+			return nil, errors.New("missing typed data domain")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported l1 sign mode %s", e.L1SignMode)
+	}
+	tx, err := ParseTxInfo(e.TxType, e.TxInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, err
+	}
+	if tx.GetL1SignatureBody() != e.L1SignBody {
+		return nil, errors.New("the l1 sign body does not match the tx info")
+	}
+	return tx, nil
+}
+
+// SignL2 signs the tx with the EdDSA key.
+func (e *Envelope) SignL2(key accounts.Signer) error {
+	tx, err := e.tx()
+	if err != nil {
+		return err
+	}
+	hFunc := mimc.NewMiMC()
+	msgHash, err := tx.Hash(hFunc)
+	if err != nil {
+		return err
+	}
+	hFunc.Reset()
+	sig, err := key.Sign(msgHash, hFunc)
+	if err != nil {
+		return err
+	}
+	e.L2Sig = hexutil.Encode(sig)
+	return nil
+}
+
+// SignL1 signs the L1 sign body with the L1 key as a personal message. The L1 address of a
+// ChangePubKey tx must be the address of the signer.
+func (e *Envelope) SignL1(l1Signer signer.L1Signer) error {
+	tx, err := e.l1SignedTx(l1Signer)
+	if err != nil {
+		return err
+	}
+	l1Sig, err := l1Signer.Sign(tx.GetL1SignatureBody())
+	if err != nil {
+		return err
+	}
+	e.L1Sig, e.L1SignMode, e.TypedDataDomain = l1Sig, "", nil
+	return nil
+}
+
+// SignL1TypedData signs the EIP-712 typed data of the tx in the given domain with the L1 key,
+// which has to implement signer.TypedDataSigner. Like the typed data sign mode of the client,
+// it is experimental.
+func (e *Envelope) SignL1TypedData(l1Signer signer.L1Signer, domain apitypes.TypedDataDomain) error {
+	tx, err := e.l1SignedTx(l1Signer)
+	if err != nil {
+		return err
+	}
+	typedDataSigner, ok := l1Signer.(signer.TypedDataSigner)
+	if !ok {
+		return fmt.Errorf("the l1 signer %T can't sign typed data", l1Signer)
+	}
+	typedData, err := BuildTypedData(tx, domain)
+	if err != nil {
+		return err
+	}
+	l1Sig, err := typedDataSigner.SignTypedData(*typedData)
+	if err != nil {
+		return err
+	}
+	e.L1Sig, e.L1SignMode, e.TypedDataDomain = l1Sig, signer.L1SignModeTypedData, &domain
+	return nil
+}
+
+// l1SignedTx returns the tx to be signed by the L1 signer.
+func (e *Envelope) l1SignedTx(l1Signer signer.L1Signer) (txtypes.TxInfo, error) {
+	tx, err := e.tx()
+	if err != nil {
+		return nil, err
+	}
+	if e.L1SignBody == "" {
+		return nil, fmt.Errorf("l2 tx type %d has no l1 signature", e.TxType)
+	}
+	if changePubKey, ok := tx.(*txtypes.ChangePubKeyInfo); ok && !strings.EqualFold(changePubKey.L1Address, l1Signer.GetAddress()) {
+		return nil, fmt.Errorf("l1 address %s does not match the l1 signer %s", changePubKey.L1Address, l1Signer.GetAddress())
+	}
+	// envelopes of version 1 can't hold the sign mode of the new signature
+	e.Version = EnvelopeVersion
+	return tx, nil
+}
+
+// Finalize returns the tx type and the signed tx info to be sent with SendRawTx, it fails
+// with ErrEnvelopeNotSigned until all signatures are present. The EdDSA signature is verified
+// against pubKey, the hex encoded public key of the account, or the new one of a ChangePubKey
+// tx, and the L1 signature against l1Address, which is ignored for txs without L1 signature.
+func (e *Envelope) Finalize(pubKey string, l1Address string) (uint32, string, error) {
+	tx, err := e.tx()
+	if err != nil {
+		return 0, "", err
+	}
+	if e.L2Sig == "" || (e.L1SignBody != "" && e.L1Sig == "") {
+		return 0, "", ErrEnvelopeNotSigned
+	}
+	sig, err := hexutil.Decode(e.L2Sig)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid l2 signature: %v", err)
+	}
+	setSigs(tx, sig, e.L1Sig)
+	txInfo, err := json.Marshal(tx)
+	if err != nil {
+		return 0, "", err
+	}
+	if err := VerifyTxSig(e.TxType, string(txInfo), pubKey); err != nil {
+		return 0, "", fmt.Errorf("invalid l2 signature: %v", err)
+	}
+	if e.L1SignBody != "" {
+		if e.L1SignMode == signer.L1SignModeTypedData {
+			err = VerifyTxL1TypedDataSig(e.TxType, string(txInfo), l1Address, *e.TypedDataDomain)
+		} else {
+			err = VerifyTxL1Sig(e.TxType, string(txInfo), l1Address)
+		}
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid l1 signature: %v", err)
+		}
+	}
+	return e.TxType, string(txInfo), nil
+}
+
+// CombineEnvelopes merges the signatures of envelopes of the same tx, e.g. one signed with
+// the EdDSA key and one signed with the L1 key.
+func CombineEnvelopes(envelopes ...*Envelope) (*Envelope, error) {
+	if len(envelopes) == 0 {
+		return nil, errors.New("no envelope to combine")
+	}
+	combined := *envelopes[0]
+	if _, err := combined.tx(); err != nil {
+		return nil, err
+	}
+	for _, e := range envelopes[1:] {
+		if e.TxType != combined.TxType || e.TxInfo != combined.TxInfo || e.L1SignBody != combined.L1SignBody {
+			return nil, fmt.Errorf("%w: different txs", ErrEnvelopeMismatch)
+		}
+		if _, err := e.tx(); err != nil {
+			return nil, err
+		}
+		if err := combineSig(&combined.L2Sig, e.L2Sig); err != nil {
+			return nil, err
+		}
+		if e.L1Sig == "" {
+			continue
+		}
+		if combined.L1Sig == "" {
+			combined.Version, combined.L1Sig, combined.L1SignMode, combined.TypedDataDomain = e.Version, e.L1Sig, e.L1SignMode, e.TypedDataDomain
+		}
+		if e.L1Sig != combined.L1Sig || e.L1SignMode != combined.L1SignMode || !reflect.DeepEqual(e.TypedDataDomain, combined.TypedDataDomain) {
+			return nil, fmt.Errorf("%w: different signatures", ErrEnvelopeMismatch)
+		}
+	}
+	return &combined, nil
+}
+
+func combineSig(sig *string, other string) error {
+	switch {
+	case other == "" || other == *sig:
+	case *sig == "":
+		*sig = other
+	default:
+		return fmt.Errorf("%w: different signatures", ErrEnvelopeMismatch)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the envelope as the magic "zkbe", the version, the uvarint tx type
+// and the uvarint length prefixed tx info, EdDSA signature, L1 signature, L1 sign mode and
+// JSON encoded typed data domain, the fields being empty when missing. Envelopes of version 1
+// end with the L1 signature.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if _, err := e.tx(); err != nil {
+		return nil, err
+	}
+	l2Sig, err := decodeSig(e.L2Sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid l2 signature: %v", err)
+	}
+	l1Sig, err := decodeSig(e.L1Sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid l1 signature: %v", err)
+	}
+	fields := [][]byte{[]byte(e.TxInfo), l2Sig, l1Sig}
+	if e.Version == EnvelopeVersion {
+		var domain []byte
+		if e.TypedDataDomain != nil {
+			if domain, err = json.Marshal(e.TypedDataDomain); err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, []byte(e.L1SignMode), domain)
+	}
+	var buf bytes.Buffer
+	buf.Write(envelopeMagic)
+	buf.WriteByte(e.Version)
+	writeUvarint(&buf, uint64(e.TxType))
+	for _, field := range fields {
+		writeUvarint(&buf, uint64(len(field)))
+		buf.Write(field)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeUvarint(buf *bytes.Buffer, x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], x)])
+}
+
+// UnmarshalBinary decodes an envelope encoded with MarshalBinary.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, envelopeMagic) || len(data) == len(envelopeMagic) {
+		return errors.New("not a binary envelope")
+	}
+	r := bytes.NewReader(data[len(envelopeMagic):])
+	version, _ := r.ReadByte()
+	fields := make([][]byte, 5)
+	switch version {
+	case 1:
+		fields = fields[:3]
+	case EnvelopeVersion:
+	default:
+		return fmt.Errorf("unsupported envelope version %d", version)
+	}
+	txType, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("invalid tx type: %v", err)
+	}
+	for i := range fields {
+		size, err := binary.ReadUvarint(r)
+		if err != nil || size > uint64(r.Len()) {
+			return errors.New("truncated envelope")
+		}
+		fields[i] = make([]byte, size)
+		_, _ = r.Read(fields[i])
+	}
+	if r.Len() != 0 {
+		return errors.New("trailing data after the envelope")
+	}
+
+	decoded := Envelope{Version: version, TxType: uint32(txType), TxInfo: string(fields[0])}
+	if len(fields[1]) > 0 {
+		decoded.L2Sig = hexutil.Encode(fields[1])
+	}
+	if len(fields[2]) > 0 {
+		decoded.L1Sig = hexutil.Encode(fields[2])
+	}
+	if version == EnvelopeVersion {
+		decoded.L1SignMode = signer.L1SignMode(fields[3])
+		if len(fields[4]) > 0 {
+			decoded.TypedDataDomain = &apitypes.TypedDataDomain{}
+			if err := json.Unmarshal(fields[4], decoded.TypedDataDomain); err != nil {
+				return fmt.Errorf("invalid typed data domain: %v", err)
+			}
+		}
+	}
+	tx, err := ParseTxInfo(decoded.TxType, decoded.TxInfo)
+	if err != nil {
+		return err
+	}
+	decoded.L1SignBody = tx.GetL1SignatureBody()
+	if _, err := decoded.tx(); err != nil {
+		return err
+	}
+	*e = decoded
+	return nil
+}
+
+// DecodeEnvelope decodes an envelope in either the JSON or the binary encoding.
+func DecodeEnvelope(data []byte) (*Envelope, error) {
+	e := &Envelope{}
+	if bytes.HasPrefix(data, envelopeMagic) {
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	if _, err := e.tx(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func decodeSig(sig string) ([]byte, error) {
+	if sig == "" {
+		return nil, nil
+	}
+	return hexutil.Decode(sig)
+}
@@ -0,0 +1,204 @@
+package txutils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestEnvelope(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	pubKey := common.Bytes2Hex(keyManager.PubKey().Bytes())
+
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	}
+	unsigned, err := NewEnvelope(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(100)}, ops)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(types.TxTypeTransfer), unsigned.TxType)
+	assert.NotEmpty(t, unsigned.L1SignBody)
+	_, _, err = unsigned.Finalize(pubKey, l1Signer.GetAddress())
+	assert.ErrorIs(t, err, ErrEnvelopeNotSigned)
+
+	// the EdDSA key signs a JSON copy
+	data, err := json.Marshal(unsigned)
+	assert.NoError(t, err)
+	l2Signed, err := DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.NoError(t, l2Signed.SignL2(keyManager))
+	_, _, err = l2Signed.Finalize(pubKey, l1Signer.GetAddress())
+	assert.ErrorIs(t, err, ErrEnvelopeNotSigned)
+
+	// the L1 key signs a binary copy
+	data, err = unsigned.MarshalBinary()
+	assert.NoError(t, err)
+	l1Signed, err := DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, unsigned, l1Signed)
+	assert.NoError(t, l1Signed.SignL1(l1Signer))
+
+	combined, err := CombineEnvelopes(l2Signed, l1Signed)
+	assert.NoError(t, err)
+	data, err = combined.MarshalBinary()
+	assert.NoError(t, err)
+	decoded, err := DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, combined, decoded)
+	txType, txInfo, err := decoded.Finalize(pubKey, l1Signer.GetAddress())
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTxSig(txType, txInfo, pubKey))
+	assert.NoError(t, VerifyTxL1Sig(txType, txInfo, l1Signer.GetAddress()))
+
+	// the envelope of a signed tx info drops its signatures
+	fromTxInfo, err := NewEnvelopeFromTxInfo(txType, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, unsigned, fromTxInfo)
+
+	// a different signature for the same slot can't be combined
+	otherKey, _ := crypto.GenerateKey()
+	otherSigner, _ := signer.NewL1Singer(common.Bytes2Hex(crypto.FromECDSA(otherKey)))
+	other := *l1Signed
+	assert.NoError(t, other.SignL1(otherSigner))
+	_, err = CombineEnvelopes(combined, &other)
+	assert.ErrorIs(t, err, ErrEnvelopeMismatch)
+
+	// signatures which do not belong to the keys of the account are not finalized
+	other = *l2Signed
+	assert.NoError(t, other.SignL1(otherSigner))
+	_, _, err = other.Finalize(pubKey, l1Signer.GetAddress())
+	assert.ErrorContains(t, err, "invalid l1 signature")
+	otherKeyManager, err := accounts.NewSeedKeyManager(seed + "00")
+	assert.NoError(t, err)
+	other = *l1Signed
+	assert.NoError(t, other.SignL2(otherKeyManager))
+	_, _, err = other.Finalize(pubKey, l1Signer.GetAddress())
+	assert.ErrorContains(t, err, "invalid l2 signature")
+	_, err = CombineEnvelopes(combined, &Envelope{Version: EnvelopeVersion, TxType: unsigned.TxType, TxInfo: "{}"})
+	assert.ErrorIs(t, err, ErrEnvelopeMismatch)
+
+	// the signers refuse a sign body which does not belong to the tx
+	tampered := *unsigned
+	tampered.L1SignBody = "Transfer 1 BNB"
+	assert.Error(t, tampered.SignL1(l1Signer))
+	assert.Error(t, tampered.SignL2(keyManager))
+	_, err = DecodeEnvelope([]byte(`{"version":3,"tx_type":4,"tx_info":"{}"}`))
+	assert.Error(t, err)
+	_, err = DecodeEnvelope([]byte(`{"version":2,"tx_type":4,"tx_info":"{}"}`))
+	assert.Error(t, err)
+	_, err = DecodeEnvelope(data[:len(data)-1])
+	assert.Error(t, err)
+}
+
+func TestEnvelopeChangePubKey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+
+	pubKey := keyManager.PubKeyPoint()
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	}
+	envelope, err := NewEnvelope(&types.ChangePubKeyReq{L1Address: "0x000000000000000000000000000000000000dEaD", PubKeyX: pubKey[0], PubKeyY: pubKey[1]}, ops)
+	assert.NoError(t, err)
+	// only the owner of the l1 address may sign
+	assert.Error(t, envelope.SignL1(l1Signer))
+}
+
+func TestEnvelopeTypedData(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	pubKey := common.Bytes2Hex(keyManager.PubKey().Bytes())
+	domain := TypedDataDomain(97, "0x000000000000000000000000000000000000bEEF")
+
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	}
+	unsigned, err := NewEnvelope(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(100)}, ops)
+	assert.NoError(t, err)
+	l2Signed := *unsigned
+	assert.NoError(t, l2Signed.SignL2(keyManager))
+
+	// the sign mode and the domain survive both encodings and the combination
+	l1Signed := *unsigned
+	assert.NoError(t, l1Signed.SignL1TypedData(l1Signer, domain))
+	assert.Equal(t, signer.L1SignModeTypedData, l1Signed.L1SignMode)
+	data, err := l1Signed.MarshalBinary()
+	assert.NoError(t, err)
+	decoded, err := DecodeEnvelope(data)
+	assert.NoError(t, err)
+	combined, err := CombineEnvelopes(&l2Signed, decoded)
+	assert.NoError(t, err)
+	data, err = json.Marshal(combined)
+	assert.NoError(t, err)
+	decoded, err = DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.L1SignModeTypedData, decoded.L1SignMode)
+	txType, txInfo, err := decoded.Finalize(pubKey, l1Signer.GetAddress())
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTxL1TypedDataSig(txType, txInfo, l1Signer.GetAddress(), domain))
+
+	// a typed data signature can't be passed off as a personal one
+	tampered := *combined
+	tampered.L1SignMode, tampered.TypedDataDomain = "", nil
+	_, _, err = tampered.Finalize(pubKey, l1Signer.GetAddress())
+	assert.ErrorContains(t, err, "invalid l1 signature")
+	tampered.L1SignMode = signer.L1SignModeTypedData
+	_, _, err = tampered.Finalize(pubKey, l1Signer.GetAddress())
+	assert.Error(t, err)
+	personal := *unsigned
+	assert.NoError(t, personal.SignL1(l1Signer))
+	_, err = CombineEnvelopes(combined, &personal)
+	assert.ErrorIs(t, err, ErrEnvelopeMismatch)
+
+	// envelopes of version 1 only hold personal signatures
+	v1 := personal
+	v1.Version = 1
+	data, err = v1.MarshalBinary()
+	assert.NoError(t, err)
+	decoded, err = DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &v1, decoded)
+	combined, err = CombineEnvelopes(&l2Signed, decoded)
+	assert.NoError(t, err)
+	_, _, err = combined.Finalize(pubKey, l1Signer.GetAddress())
+	assert.NoError(t, err)
+	v1.L1SignMode, v1.TypedDataDomain = signer.L1SignModeTypedData, &domain
+	_, err = v1.MarshalBinary()
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package txutils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// ParseTxInfo decodes the tx info of an l2 tx which can be sent with SendRawTx.
+func ParseTxInfo(txType uint32, txInfo string) (txtypes.TxInfo, error) {
+	var tx txtypes.TxInfo
+	switch txType {
+	case types.TxTypeChangePubKey:
+		tx = &txtypes.ChangePubKeyInfo{}
+	case types.TxTypeTransfer:
+		tx = &txtypes.TransferTxInfo{}
+	case types.TxTypeWithdraw:
+		tx = &txtypes.WithdrawTxInfo{}
+	case types.TxTypeCreateCollection:
+		tx = &txtypes.CreateCollectionTxInfo{}
+	case types.TxTypeMintNft:
+		tx = &txtypes.MintNftTxInfo{}
+	case types.TxTypeTransferNft:
+		tx = &txtypes.TransferNftTxInfo{}
+	case types.TxTypeAtomicMatch:
+		tx = &txtypes.AtomicMatchTxInfo{}
+	case types.TxTypeCancelOffer:
+		tx = &txtypes.CancelOfferTxInfo{}
+	case types.TxTypeWithdrawNft:
+		tx = &txtypes.WithdrawNftTxInfo{}
+	default:
+		return nil, fmt.Errorf("unsupported l2 tx type %d", txType)
+	}
+	if err := json.Unmarshal([]byte(txInfo), tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ComputeTxHash computes the hash ZkBNB assigns to the tx, which can be used to
+// query it with GetTx before the server has returned it.
+func ComputeTxHash(txType uint32, txInfo string) (string, error) {
+	tx, err := ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Validate(); err != nil {
+		return "", err
+	}
+	msgHash, err := tx.Hash(mimc.NewMiMC())
+	if err != nil {
+		return "", err
+	}
+	return common.Bytes2Hex(msgHash), nil
+}
@@ -2,12 +2,16 @@ package txutils
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
 	"github.com/bnb-chain/zkbnb-go-sdk/types"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 type PublicKey = eddsa.PublicKey
@@ -359,3 +363,178 @@ func VerifyAtomicMatchTxSig(pubKey string, tx *types.AtomicMatchTxInfo) error {
 	}
 	return nil
 }
+
+func VerifyTransferTxSig(pubKey string, tx *types.TransferTxInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
+	if err != nil {
+		return err
+	}
+
+	pk, err := parsePk(pubKey)
+	if err != nil {
+		return err
+	}
+	hFunc := mimc.NewMiMC()
+	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func VerifyWithdrawTxSig(pubKey string, tx *types.WithdrawTxInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
+	if err != nil {
+		return err
+	}
+
+	pk, err := parsePk(pubKey)
+	if err != nil {
+		return err
+	}
+	hFunc := mimc.NewMiMC()
+	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// VerifyChangePubKeyTxSig verifies the signature of a ChangePubKey tx, which is made with the
+// new key of the account, against pubKey.
+func VerifyChangePubKeyTxSig(pubKey string, tx *txtypes.ChangePubKeyInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
+	if err != nil {
+		return err
+	}
+
+	pk, err := parsePk(pubKey)
+	if err != nil {
+		return err
+	}
+	hFunc := mimc.NewMiMC()
+	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// VerifyTxSig verifies the EdDSA signature of a tx, given by its type and tx info as sent
+// with SendRawTx, against the hex encoded compressed public key of the signer. Offers can be
+// verified with TxTypeOffer.
+func VerifyTxSig(txType uint32, txInfo string, pubKey string) error {
+	if txType == types.TxTypeOffer {
+		tx := &types.OfferTxInfo{}
+		if err := json.Unmarshal([]byte(txInfo), tx); err != nil {
+			return err
+		}
+		return VerifyOfferTxSig(pubKey, tx)
+	}
+	tx, err := ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return err
+	}
+	switch tx := tx.(type) {
+	case *txtypes.ChangePubKeyInfo:
+		return VerifyChangePubKeyTxSig(pubKey, tx)
+	case *txtypes.TransferTxInfo:
+		return VerifyTransferTxSig(pubKey, tx)
+	case *txtypes.WithdrawTxInfo:
+		return VerifyWithdrawTxSig(pubKey, tx)
+	case *txtypes.CreateCollectionTxInfo:
+		return VerifyCreateCollectionTxSig(pubKey, tx)
+	case *txtypes.MintNftTxInfo:
+		return VerifyMintNftTxSig(pubKey, tx)
+	case *txtypes.TransferNftTxInfo:
+		return VerifyTransferNftTxSig(pubKey, tx)
+	case *txtypes.AtomicMatchTxInfo:
+		return VerifyAtomicMatchTxSig(pubKey, tx)
+	case *txtypes.CancelOfferTxInfo:
+		return VerifyCancelOfferTxSig(pubKey, tx)
+	case *txtypes.WithdrawNftTxInfo:
+		return VerifyWithdrawNftTxSig(pubKey, tx)
+	default:
+		return fmt.Errorf("unsupported l2 tx type %d", txType)
+	}
+}
+
+// VerifyTxL1Sig verifies that the L1Sig of a tx, given by its type and tx info as sent with
+// SendRawTx, was made by l1Address over the signature body of the tx. Offers can be verified
+// with TxTypeOffer. AtomicMatch txs carry no L1 signature of their own and are rejected.
+func VerifyTxL1Sig(txType uint32, txInfo string, l1Address string) error {
+	tx, err := parseL1SignedTx(txType, txInfo)
+	if err != nil {
+		return err
+	}
+	return VerifyL1Sig(tx, l1Address)
+}
+
+// parseL1SignedTx parses the tx info of the l2 txs and of the offers, which are signed by
+// the L1 key as well.
+func parseL1SignedTx(txType uint32, txInfo string) (txtypes.TxInfo, error) {
+	if txType == types.TxTypeOffer {
+		tx := &types.OfferTxInfo{}
+		if err := json.Unmarshal([]byte(txInfo), tx); err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+	return ParseTxInfo(txType, txInfo)
+}
+
+// VerifyL1Sig verifies that the L1Sig of the tx was made by l1Address over the signature
+// body returned by GetL1SignatureBody.
+func VerifyL1Sig(tx txtypes.TxInfo, l1Address string) error {
+	if !common.IsHexAddress(l1Address) {
+		return fmt.Errorf("invalid l1 address %s", l1Address)
+	}
+	if tx.GetL1SignatureBody() == "" {
+		return fmt.Errorf("l2 tx type %d has no l1 signature", tx.GetTxType())
+	}
+	// GetL1AddressBySignature panics on signatures shorter than 65 bytes
+	l1Sig, err := hexutil.Decode(l1SigOf(tx))
+	if err != nil || len(l1Sig) != crypto.SignatureLength {
+		return fmt.Errorf("invalid l1 signature")
+	}
+	if tx.GetL1AddressBySignature() != common.HexToAddress(l1Address) {
+		return fmt.Errorf("invalid l1 signature")
+	}
+	return nil
+}
+
+func l1SigOf(tx txtypes.TxInfo) string {
+	switch tx := tx.(type) {
+	case *txtypes.ChangePubKeyInfo:
+		return tx.L1Sig
+	case *txtypes.TransferTxInfo:
+		return tx.L1Sig
+	case *txtypes.WithdrawTxInfo:
+		return tx.L1Sig
+	case *txtypes.CreateCollectionTxInfo:
+		return tx.L1Sig
+	case *txtypes.MintNftTxInfo:
+		return tx.L1Sig
+	case *txtypes.TransferNftTxInfo:
+		return tx.L1Sig
+	case *txtypes.CancelOfferTxInfo:
+		return tx.L1Sig
+	case *txtypes.WithdrawNftTxInfo:
+		return tx.L1Sig
+	case *txtypes.OfferTxInfo:
+		return tx.L1Sig
+	case *txtypes.UpdateNFTTxInfo:
+		return tx.L1Sig
+	default:
+		return ""
+	}
+}
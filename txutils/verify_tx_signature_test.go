@@ -0,0 +1,93 @@
+package txutils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestVerifyTxSig(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	pubKey := common.Bytes2Hex(keyManager.PubKey().Bytes())
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             3,
+		ToAccountAddress:  "0x000000000000000000000000000000000000dEaD",
+		CallDataHash:      crypto.Keccak256([]byte{}),
+	}
+	tx, err := ConstructTransferTx(keyManager, ops, &types.TransferTxReq{AssetAmount: big.NewInt(100)})
+	assert.NoError(t, err)
+	tx.L1Sig, err = l1Signer.Sign(tx.GetL1SignatureBody())
+	assert.NoError(t, err)
+	txInfo, _ := json.Marshal(tx)
+
+	assert.NoError(t, VerifyTxSig(types.TxTypeTransfer, string(txInfo), pubKey))
+	assert.NoError(t, VerifyTxL1Sig(types.TxTypeTransfer, string(txInfo), l1Address))
+
+	otherKey, _ := crypto.GenerateKey()
+	otherSeed, _ := accounts.GenerateSeed(common.Bytes2Hex(crypto.FromECDSA(otherKey)), 97)
+	other, _ := accounts.NewSeedKeyManager(otherSeed)
+	assert.Error(t, VerifyTxSig(types.TxTypeTransfer, string(txInfo), common.Bytes2Hex(other.PubKey().Bytes())))
+	assert.Error(t, VerifyTxL1Sig(types.TxTypeTransfer, string(txInfo), "0x000000000000000000000000000000000000dEaD"))
+	assert.Error(t, VerifyTxSig(types.TxTypeWithdraw, string(txInfo), pubKey))
+	assert.Error(t, VerifyTxSig(types.TxTypeDeposit, string(txInfo), pubKey))
+
+	// the signatures cover the amount
+	tx.AssetAmount = big.NewInt(101)
+	txInfo, _ = json.Marshal(tx)
+	assert.Error(t, VerifyTxSig(types.TxTypeTransfer, string(txInfo), pubKey))
+	assert.Error(t, VerifyTxL1Sig(types.TxTypeTransfer, string(txInfo), l1Address))
+
+	// malformed l1 signatures are rejected instead of panicking
+	tx.L1Sig = "0x1234"
+	txInfo, _ = json.Marshal(tx)
+	assert.Error(t, VerifyTxL1Sig(types.TxTypeTransfer, string(txInfo), l1Address))
+}
+
+func TestVerifyChangePubKeyTxSig(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	seed, err := accounts.GenerateSeed(privateKey, 97)
+	assert.NoError(t, err)
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+
+	pubKey := keyManager.PubKeyPoint()
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	}
+	tx, err := ConstructChangePubKeyTx(keyManager, &types.ChangePubKeyReq{L1Address: l1Signer.GetAddress(), PubKeyX: pubKey[0], PubKeyY: pubKey[1]}, ops)
+	assert.NoError(t, err)
+	tx.L1Sig, err = l1Signer.Sign(tx.GetL1SignatureBody())
+	assert.NoError(t, err)
+	txInfo, _ := json.Marshal(tx)
+
+	assert.NoError(t, VerifyTxSig(types.TxTypeChangePubKey, string(txInfo), tx.GetPubKey()))
+	assert.NoError(t, VerifyTxL1Sig(types.TxTypeChangePubKey, string(txInfo), l1Signer.GetAddress()))
+}
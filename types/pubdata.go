@@ -0,0 +1,417 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PubDataBytesPerTx is the size of the pubdata chunk of a tx, 968 bits. The pubdata of an
+// operation is zero padded to it.
+const PubDataBytesPerTx = 121
+
+var maxFeeMantissa = big.NewInt(1<<11 - 1)
+
+// OnChainOperation is an operation of a block which is processed by the ZkBNB contract on L1,
+// see DecodeOnChainOperation. It is one of *DepositOperation, *DepositNftOperation,
+// *WithdrawOperation, *WithdrawNftOperation, *FullExitOperation, *FullExitNftOperation or
+// *ChangePubKeyOperation.
+type OnChainOperation interface {
+	GetTxType() int
+}
+
+type DepositOperation struct {
+	AccountIndex int64
+	L1Address    string
+	AssetId      int64
+	AssetAmount  *big.Int
+}
+
+type DepositNftOperation struct {
+	AccountIndex        int64
+	CreatorAccountIndex int64
+	RoyaltyRate         int64
+	NftIndex            int64
+	CollectionId        int64
+	L1Address           string
+	NftContentHash      []byte
+	NftContentType      int64
+}
+
+type WithdrawOperation struct {
+	FromAccountIndex  int64
+	ToAddress         string
+	AssetId           int64
+	AssetAmount       *big.Int
+	GasFeeAssetId     int64
+	GasFeeAssetAmount *big.Int
+}
+
+type WithdrawNftOperation struct {
+	AccountIndex        int64
+	CreatorAccountIndex int64
+	RoyaltyRate         int64
+	NftIndex            int64
+	CollectionId        int64
+	GasFeeAssetId       int64
+	GasFeeAssetAmount   *big.Int
+	ToAddress           string
+	CreatorL1Address    string
+	NftContentHash      []byte
+	NftContentType      int64
+}
+
+type FullExitOperation struct {
+	AccountIndex int64
+	AssetId      int64
+	AssetAmount  *big.Int
+	L1Address    string
+}
+
+type FullExitNftOperation struct {
+	AccountIndex        int64
+	CreatorAccountIndex int64
+	RoyaltyRate         int64
+	NftIndex            int64
+	CollectionId        int64
+	L1Address           string
+	CreatorL1Address    string
+	NftContentHash      []byte
+	NftContentType      int64
+}
+
+type ChangePubKeyOperation struct {
+	AccountIndex      int64
+	PubKeyX           []byte
+	PubKeyY           []byte
+	L1Address         string
+	Nonce             int64
+	GasFeeAssetId     int64
+	GasFeeAssetAmount *big.Int
+}
+
+func (op *DepositOperation) GetTxType() int      { return TxTypeDeposit }
+func (op *DepositNftOperation) GetTxType() int   { return TxTypeDepositNft }
+func (op *WithdrawOperation) GetTxType() int     { return TxTypeWithdraw }
+func (op *WithdrawNftOperation) GetTxType() int  { return TxTypeWithdrawNft }
+func (op *FullExitOperation) GetTxType() int     { return TxTypeFullExit }
+func (op *FullExitNftOperation) GetTxType() int  { return TxTypeFullExitNft }
+func (op *ChangePubKeyOperation) GetTxType() int { return TxTypeChangePubKey }
+
+// DecodeOnChainOperation decodes the pubdata of an on-chain operation: the tx type byte
+// followed by the big endian fields of the operation, in the order of the operation structs.
+// The gas fees are packed on 16 bits, 11 bits of mantissa and 5 bits of base 10 exponent.
+// The pubdata may be zero padded up to PubDataBytesPerTx.
+func DecodeOnChainOperation(pubData []byte) (OnChainOperation, error) {
+	if len(pubData) == 0 {
+		return nil, errors.New("empty pubdata")
+	}
+	if len(pubData) > PubDataBytesPerTx {
+		return nil, fmt.Errorf("pubdata of %d bytes is longer than a tx chunk", len(pubData))
+	}
+	r := &pubDataReader{data: pubData, offset: 1}
+	var op OnChainOperation
+	switch txType := pubData[0]; txType {
+	case TxTypeDeposit:
+		op = &DepositOperation{
+			AccountIndex: r.int(4),
+			L1Address:    r.address(),
+			AssetId:      r.int(2),
+			AssetAmount:  r.bigInt(16),
+		}
+	case TxTypeDepositNft:
+		op = &DepositNftOperation{
+			AccountIndex:        r.int(4),
+			CreatorAccountIndex: r.int(4),
+			RoyaltyRate:         r.int(2),
+			NftIndex:            r.int(5),
+			CollectionId:        r.int(2),
+			L1Address:           r.address(),
+			NftContentHash:      r.bytes(32),
+			NftContentType:      r.int(1),
+		}
+	case TxTypeWithdraw:
+		op = &WithdrawOperation{
+			FromAccountIndex:  r.int(4),
+			ToAddress:         r.address(),
+			AssetId:           r.int(2),
+			AssetAmount:       r.bigInt(16),
+			GasFeeAssetId:     r.int(2),
+			GasFeeAssetAmount: r.packedFee(),
+		}
+	case TxTypeWithdrawNft:
+		op = &WithdrawNftOperation{
+			AccountIndex:        r.int(4),
+			CreatorAccountIndex: r.int(4),
+			RoyaltyRate:         r.int(2),
+			NftIndex:            r.int(5),
+			CollectionId:        r.int(2),
+			GasFeeAssetId:       r.int(2),
+			GasFeeAssetAmount:   r.packedFee(),
+			ToAddress:           r.address(),
+			CreatorL1Address:    r.address(),
+			NftContentHash:      r.bytes(32),
+			NftContentType:      r.int(1),
+		}
+	case TxTypeFullExit:
+		op = &FullExitOperation{
+			AccountIndex: r.int(4),
+			AssetId:      r.int(2),
+			AssetAmount:  r.bigInt(16),
+			L1Address:    r.address(),
+		}
+	case TxTypeFullExitNft:
+		op = &FullExitNftOperation{
+			AccountIndex:        r.int(4),
+			CreatorAccountIndex: r.int(4),
+			RoyaltyRate:         r.int(2),
+			NftIndex:            r.int(5),
+			CollectionId:        r.int(2),
+			L1Address:           r.address(),
+			CreatorL1Address:    r.address(),
+			NftContentHash:      r.bytes(32),
+			NftContentType:      r.int(1),
+		}
+	case TxTypeChangePubKey:
+		op = &ChangePubKeyOperation{
+			AccountIndex:      r.int(4),
+			PubKeyX:           r.bytes(32),
+			PubKeyY:           r.bytes(32),
+			L1Address:         r.address(),
+			Nonce:             r.int(4),
+			GasFeeAssetId:     r.int(2),
+			GasFeeAssetAmount: r.packedFee(),
+		}
+	default:
+		return nil, fmt.Errorf("tx type %d is not an on-chain operation", txType)
+	}
+	if r.offset > len(pubData) {
+		return nil, fmt.Errorf("truncated pubdata of tx type %d", pubData[0])
+	}
+	for _, b := range pubData[r.offset:] {
+		if b != 0 {
+			return nil, fmt.Errorf("non zero padding in the pubdata of tx type %d", pubData[0])
+		}
+	}
+	return op, nil
+}
+
+// EncodeOnChainOperation encodes an on-chain operation into a zero padded pubdata chunk of
+// PubDataBytesPerTx bytes. Gas fees which cannot be packed without loss are an error.
+func EncodeOnChainOperation(op OnChainOperation) ([]byte, error) {
+	w := &pubDataWriter{}
+	switch op := op.(type) {
+	case *DepositOperation:
+		w.int(TxTypeDeposit, 1)
+		w.int(op.AccountIndex, 4)
+		w.address(op.L1Address)
+		w.int(op.AssetId, 2)
+		w.bigInt(op.AssetAmount, 16)
+	case *DepositNftOperation:
+		w.int(TxTypeDepositNft, 1)
+		w.int(op.AccountIndex, 4)
+		w.int(op.CreatorAccountIndex, 4)
+		w.int(op.RoyaltyRate, 2)
+		w.int(op.NftIndex, 5)
+		w.int(op.CollectionId, 2)
+		w.address(op.L1Address)
+		w.bytes(op.NftContentHash, 32)
+		w.int(op.NftContentType, 1)
+	case *WithdrawOperation:
+		w.int(TxTypeWithdraw, 1)
+		w.int(op.FromAccountIndex, 4)
+		w.address(op.ToAddress)
+		w.int(op.AssetId, 2)
+		w.bigInt(op.AssetAmount, 16)
+		w.int(op.GasFeeAssetId, 2)
+		w.packedFee(op.GasFeeAssetAmount)
+	case *WithdrawNftOperation:
+		w.int(TxTypeWithdrawNft, 1)
+		w.int(op.AccountIndex, 4)
+		w.int(op.CreatorAccountIndex, 4)
+		w.int(op.RoyaltyRate, 2)
+		w.int(op.NftIndex, 5)
+		w.int(op.CollectionId, 2)
+		w.int(op.GasFeeAssetId, 2)
+		w.packedFee(op.GasFeeAssetAmount)
+		w.address(op.ToAddress)
+		w.address(op.CreatorL1Address)
+		w.bytes(op.NftContentHash, 32)
+		w.int(op.NftContentType, 1)
+	case *FullExitOperation:
+		w.int(TxTypeFullExit, 1)
+		w.int(op.AccountIndex, 4)
+		w.int(op.AssetId, 2)
+		w.bigInt(op.AssetAmount, 16)
+		w.address(op.L1Address)
+	case *FullExitNftOperation:
+		w.int(TxTypeFullExitNft, 1)
+		w.int(op.AccountIndex, 4)
+		w.int(op.CreatorAccountIndex, 4)
+		w.int(op.RoyaltyRate, 2)
+		w.int(op.NftIndex, 5)
+		w.int(op.CollectionId, 2)
+		w.address(op.L1Address)
+		w.address(op.CreatorL1Address)
+		w.bytes(op.NftContentHash, 32)
+		w.int(op.NftContentType, 1)
+	case *ChangePubKeyOperation:
+		w.int(TxTypeChangePubKey, 1)
+		w.int(op.AccountIndex, 4)
+		w.bytes(op.PubKeyX, 32)
+		w.bytes(op.PubKeyY, 32)
+		w.address(op.L1Address)
+		w.int(op.Nonce, 4)
+		w.int(op.GasFeeAssetId, 2)
+		w.packedFee(op.GasFeeAssetAmount)
+	default:
+		return nil, fmt.Errorf("unsupported on-chain operation %T", op)
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+	return append(w.buf.Bytes(), make([]byte, PubDataBytesPerTx-w.buf.Len())...), nil
+}
+
+// DecodePendingOnChainOperations decodes the PendingOnChainOperationsPubData of a block, a JSON
+// list of the base64 encoded pubdata of the operations processed on L1 when the block is
+// verified. It is empty for the blocks without such operation.
+func DecodePendingOnChainOperations(pubData string) ([]OnChainOperation, error) {
+	if pubData == "" {
+		return nil, nil
+	}
+	var chunks [][]byte
+	if err := json.Unmarshal([]byte(pubData), &chunks); err != nil {
+		return nil, fmt.Errorf("invalid pending on-chain operations pubdata: %v", err)
+	}
+	ops := make([]OnChainOperation, 0, len(chunks))
+	for i, chunk := range chunks {
+		op, err := DecodeOnChainOperation(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pending on-chain operation %d: %v", i, err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// EncodePendingOnChainOperations encodes operations like the PendingOnChainOperationsPubData
+// of a block, see DecodePendingOnChainOperations.
+func EncodePendingOnChainOperations(ops ...OnChainOperation) (string, error) {
+	if len(ops) == 0 {
+		return "", nil
+	}
+	chunks := make([][]byte, 0, len(ops))
+	for _, op := range ops {
+		chunk, err := EncodeOnChainOperation(op)
+		if err != nil {
+			return "", err
+		}
+		chunks = append(chunks, chunk)
+	}
+	pubData, err := json.Marshal(chunks)
+	if err != nil {
+		return "", err
+	}
+	return string(pubData), nil
+}
+
+// pubDataReader reads big endian fields, reading past the end of the data yields zeros and
+// moves the offset past the end.
+type pubDataReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *pubDataReader) bytes(size int) []byte {
+	field := make([]byte, size)
+	if r.offset < len(r.data) {
+		copy(field, r.data[r.offset:])
+	}
+	r.offset += size
+	return field
+}
+
+func (r *pubDataReader) int(size int) int64 {
+	return r.bigInt(size).Int64()
+}
+
+func (r *pubDataReader) bigInt(size int) *big.Int {
+	return new(big.Int).SetBytes(r.bytes(size))
+}
+
+func (r *pubDataReader) address() string {
+	return common.BytesToAddress(r.bytes(common.AddressLength)).Hex()
+}
+
+func (r *pubDataReader) packedFee() *big.Int {
+	return unpackFee(r.int(2))
+}
+
+// pubDataWriter writes big endian fields and keeps the first error.
+type pubDataWriter struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (w *pubDataWriter) bytes(field []byte, size int) {
+	if w.err == nil && len(field) != size {
+		w.err = fmt.Errorf("pubdata field of %d bytes instead of %d", len(field), size)
+	}
+	w.buf.Write(common.LeftPadBytes(field, size)[:size])
+}
+
+func (w *pubDataWriter) int(value int64, size int) {
+	w.bigInt(big.NewInt(value), size)
+}
+
+func (w *pubDataWriter) bigInt(value *big.Int, size int) {
+	if value == nil || value.Sign() < 0 || value.BitLen() > size*8 {
+		if w.err == nil {
+			w.err = fmt.Errorf("value %v does not fit %d bytes of pubdata", value, size)
+		}
+		value = new(big.Int)
+	}
+	w.buf.Write(value.FillBytes(make([]byte, size)))
+}
+
+func (w *pubDataWriter) address(address string) {
+	if w.err == nil && !common.IsHexAddress(address) {
+		w.err = fmt.Errorf("invalid address %q", address)
+	}
+	w.buf.Write(common.HexToAddress(address).Bytes())
+}
+
+func (w *pubDataWriter) packedFee(fee *big.Int) {
+	packed, ok := packFee(fee)
+	if w.err == nil && !ok {
+		w.err = fmt.Errorf("gas fee %v cannot be packed", fee)
+	}
+	w.int(packed, 2)
+}
+
+// packFee packs a fee on 16 bits, 11 bits of mantissa followed by 5 bits of base 10 exponent,
+// the fee must be packed without loss.
+func packFee(fee *big.Int) (int64, bool) {
+	if fee == nil || fee.Sign() < 0 {
+		return 0, false
+	}
+	mantissa, exponent := new(big.Int).Set(fee), int64(0)
+	ten, rem := big.NewInt(10), new(big.Int)
+	for mantissa.Cmp(maxFeeMantissa) > 0 {
+		if mantissa.QuoRem(mantissa, ten, rem); rem.Sign() != 0 || exponent == 0x1f {
+			return 0, false
+		}
+		exponent++
+	}
+	return mantissa.Int64()<<5 | exponent, true
+}
+
+func unpackFee(packed int64) *big.Int {
+	fee := new(big.Int).Exp(big.NewInt(10), big.NewInt(packed&0x1f), nil)
+	return fee.Mul(fee, big.NewInt(packed>>5))
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnChainOperationPubData(t *testing.T) {
+	address := "0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"
+	creator := "0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"
+	hash := bytes.Repeat([]byte{0xab}, 32)
+	amount, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	ops := []OnChainOperation{
+		&DepositOperation{AccountIndex: 3, L1Address: address, AssetId: 1, AssetAmount: amount},
+		&DepositNftOperation{AccountIndex: 3, CreatorAccountIndex: 4, RoyaltyRate: 250, NftIndex: 1 << 39, CollectionId: 2, L1Address: address, NftContentHash: hash, NftContentType: 1},
+		&WithdrawOperation{FromAccountIndex: 3, ToAddress: address, AssetId: 0, AssetAmount: big.NewInt(1e18), GasFeeAssetId: 1, GasFeeAssetAmount: big.NewInt(2047e12)},
+		&WithdrawNftOperation{AccountIndex: 3, CreatorAccountIndex: 4, RoyaltyRate: 250, NftIndex: 7, CollectionId: 2, GasFeeAssetId: 0, GasFeeAssetAmount: big.NewInt(5), ToAddress: address, CreatorL1Address: creator, NftContentHash: hash},
+		&FullExitOperation{AccountIndex: 3, AssetId: 1, AssetAmount: big.NewInt(1), L1Address: address},
+		&FullExitNftOperation{AccountIndex: 3, CreatorAccountIndex: 4, RoyaltyRate: 250, NftIndex: 7, CollectionId: 2, L1Address: address, CreatorL1Address: creator, NftContentHash: hash, NftContentType: 1},
+		&ChangePubKeyOperation{AccountIndex: 3, PubKeyX: hash, PubKeyY: bytes.Repeat([]byte{0xcd}, 32), L1Address: address, Nonce: 9, GasFeeAssetId: 0, GasFeeAssetAmount: big.NewInt(1e14)},
+	}
+	for _, op := range ops {
+		pubData, err := EncodeOnChainOperation(op)
+		assert.NoError(t, err)
+		assert.Len(t, pubData, PubDataBytesPerTx)
+		assert.Equal(t, byte(op.GetTxType()), pubData[0])
+		decoded, err := DecodeOnChainOperation(pubData)
+		assert.NoError(t, err)
+		assert.Equal(t, op, decoded)
+	}
+
+	// the fields are big endian and the padding may be left out, as in the L1 priority requests
+	pubData, _ := EncodeOnChainOperation(ops[0])
+	assert.Equal(t, []byte{TxTypeDeposit, 0, 0, 0, 3}, pubData[:5])
+	decoded, err := DecodeOnChainOperation(pubData[:43])
+	assert.NoError(t, err)
+	assert.Equal(t, ops[0], decoded)
+	_, err = DecodeOnChainOperation(pubData[:42])
+	assert.Error(t, err)
+	pubData[43] = 1
+	_, err = DecodeOnChainOperation(pubData)
+	assert.Error(t, err)
+	_, err = DecodeOnChainOperation(append(make([]byte, PubDataBytesPerTx), 0))
+	assert.Error(t, err)
+	_, err = DecodeOnChainOperation([]byte{TxTypeTransfer})
+	assert.Error(t, err)
+
+	// the gas fees must be packed without loss
+	_, err = EncodeOnChainOperation(&WithdrawOperation{ToAddress: address, AssetAmount: big.NewInt(1), GasFeeAssetAmount: big.NewInt(2048)})
+	assert.Error(t, err)
+	_, err = EncodeOnChainOperation(&WithdrawOperation{ToAddress: address, AssetAmount: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = EncodeOnChainOperation(&DepositOperation{L1Address: address, AssetAmount: new(big.Int).Add(amount, big.NewInt(1))})
+	assert.Error(t, err)
+	_, err = EncodeOnChainOperation(&DepositOperation{L1Address: "0x1234", AssetAmount: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = EncodeOnChainOperation(&FullExitNftOperation{L1Address: address, CreatorL1Address: creator, NftContentHash: hash[:16]})
+	assert.Error(t, err)
+}
+
+func TestPendingOnChainOperations(t *testing.T) {
+	ops := []OnChainOperation{
+		&WithdrawOperation{FromAccountIndex: 3, ToAddress: "0x5B38Da6a701c568545dCfcB03FcB875f56beddC4", AssetAmount: big.NewInt(1e18), GasFeeAssetAmount: big.NewInt(1e12)},
+		&FullExitOperation{AccountIndex: 4, AssetId: 1, AssetAmount: big.NewInt(5), L1Address: "0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"},
+	}
+	pubData, err := EncodePendingOnChainOperations(ops...)
+	assert.NoError(t, err)
+	decoded, err := DecodePendingOnChainOperations(pubData)
+	assert.NoError(t, err)
+	assert.Equal(t, ops, decoded)
+
+	decoded, err = DecodePendingOnChainOperations("")
+	assert.NoError(t, err)
+	assert.Empty(t, decoded)
+	_, err = DecodePendingOnChainOperations("[\"AQ==\"]")
+	assert.Error(t, err)
+}
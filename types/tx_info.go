@@ -0,0 +1,171 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+)
+
+// TxInfo holds the fields common to the infos of every tx type, see DecodeTxInfo. The
+// txs sent by L1, like deposits and full exits, have no nonce, expiry and gas, they return
+// -1, math.MaxInt64 and a -1 gas account and asset with a nil amount.
+type TxInfo interface {
+	GetTxType() int
+	GetAccountIndex() int64
+	GetNonce() int64
+	GetExpiredAt() int64
+	GetGas() (gasAccountIndex int64, gasFeeAssetId int64, gasFeeAssetAmount *big.Int)
+}
+
+// DecodeTxInfo decodes the info of the tx according to its type. The result is one of
+// *ChangePubKeyTxInfo, *DepositTxInfo, *DepositNftTxInfo, *TransferTxInfo, *WithdrawTxInfo,
+// *CreateCollectionTxInfo, *MintNftTxInfo, *TransferNftTxInfo, *AtomicMatchTxInfo,
+// *CancelOfferTxInfo, *WithdrawNftTxInfo, *FullExitTxInfo, *FullExitNftTxInfo,
+// *OfferTxInfo or *UpdateNftTxInfo. Empty and unknown tx types are an error.
+func DecodeTxInfo(tx *Tx) (TxInfo, error) {
+	if tx == nil {
+		return nil, errors.New("tx is nil")
+	}
+	var txInfo TxInfo
+	switch tx.Type {
+	case TxTypeChangePubKey:
+		txInfo = &ChangePubKeyTxInfo{}
+	case TxTypeDeposit:
+		txInfo = &DepositTxInfo{}
+	case TxTypeDepositNft:
+		txInfo = &DepositNftTxInfo{}
+	case TxTypeTransfer:
+		txInfo = &TransferTxInfo{}
+	case TxTypeWithdraw:
+		txInfo = &WithdrawTxInfo{}
+	case TxTypeCreateCollection:
+		txInfo = &CreateCollectionTxInfo{}
+	case TxTypeMintNft:
+		txInfo = &MintNftTxInfo{}
+	case TxTypeTransferNft:
+		txInfo = &TransferNftTxInfo{}
+	case TxTypeAtomicMatch:
+		txInfo = &AtomicMatchTxInfo{}
+	case TxTypeCancelOffer:
+		txInfo = &CancelOfferTxInfo{}
+	case TxTypeWithdrawNft:
+		txInfo = &WithdrawNftTxInfo{}
+	case TxTypeFullExit:
+		txInfo = &FullExitTxInfo{}
+	case TxTypeFullExitNft:
+		txInfo = &FullExitNftTxInfo{}
+	case TxTypeOffer:
+		txInfo = &OfferTxInfo{}
+	case TxTypeUpdateNFT:
+		txInfo = &UpdateNftTxInfo{}
+	default:
+		return nil, fmt.Errorf("unknown tx type %d", tx.Type)
+	}
+	if err := json.Unmarshal([]byte(tx.Info), txInfo); err != nil {
+		return nil, fmt.Errorf("invalid info of tx %s: %v", tx.Hash, err)
+	}
+	return txInfo, nil
+}
+
+func (txInfo *ChangePubKeyTxInfo) GetTxType() int {
+	return TxTypeChangePubKey
+}
+
+func (txInfo *ChangePubKeyTxInfo) GetAccountIndex() int64 {
+	return txInfo.AccountIndex
+}
+
+func (txInfo *ChangePubKeyTxInfo) GetNonce() int64 {
+	return txInfo.Nonce
+}
+
+func (txInfo *ChangePubKeyTxInfo) GetExpiredAt() int64 {
+	return txInfo.ExpiredAt
+}
+
+func (txInfo *ChangePubKeyTxInfo) GetGas() (int64, int64, *big.Int) {
+	return txInfo.GasAccountIndex, txInfo.GasFeeAssetId, txInfo.GasFeeAssetAmount
+}
+
+func (txInfo *DepositTxInfo) GetTxType() int {
+	return TxTypeDeposit
+}
+
+func (txInfo *DepositTxInfo) GetAccountIndex() int64 {
+	return txInfo.AccountIndex
+}
+
+func (txInfo *DepositTxInfo) GetNonce() int64 {
+	return txtypes.NilNonce
+}
+
+func (txInfo *DepositTxInfo) GetExpiredAt() int64 {
+	return txtypes.NilExpiredAt
+}
+
+func (txInfo *DepositTxInfo) GetGas() (int64, int64, *big.Int) {
+	return txtypes.NilAccountIndex, txtypes.NilAssetId, nil
+}
+
+func (txInfo *DepositNftTxInfo) GetTxType() int {
+	return TxTypeDepositNft
+}
+
+func (txInfo *DepositNftTxInfo) GetAccountIndex() int64 {
+	return txInfo.AccountIndex
+}
+
+func (txInfo *DepositNftTxInfo) GetNonce() int64 {
+	return txtypes.NilNonce
+}
+
+func (txInfo *DepositNftTxInfo) GetExpiredAt() int64 {
+	return txtypes.NilExpiredAt
+}
+
+func (txInfo *DepositNftTxInfo) GetGas() (int64, int64, *big.Int) {
+	return txtypes.NilAccountIndex, txtypes.NilAssetId, nil
+}
+
+func (txInfo *FullExitTxInfo) GetTxType() int {
+	return TxTypeFullExit
+}
+
+func (txInfo *FullExitTxInfo) GetAccountIndex() int64 {
+	return txInfo.AccountIndex
+}
+
+func (txInfo *FullExitTxInfo) GetNonce() int64 {
+	return txtypes.NilNonce
+}
+
+func (txInfo *FullExitTxInfo) GetExpiredAt() int64 {
+	return txtypes.NilExpiredAt
+}
+
+func (txInfo *FullExitTxInfo) GetGas() (int64, int64, *big.Int) {
+	return txtypes.NilAccountIndex, txtypes.NilAssetId, nil
+}
+
+func (txInfo *FullExitNftTxInfo) GetTxType() int {
+	return TxTypeFullExitNft
+}
+
+func (txInfo *FullExitNftTxInfo) GetAccountIndex() int64 {
+	return txInfo.AccountIndex
+}
+
+func (txInfo *FullExitNftTxInfo) GetNonce() int64 {
+	return txtypes.NilNonce
+}
+
+func (txInfo *FullExitNftTxInfo) GetExpiredAt() int64 {
+	return txtypes.NilExpiredAt
+}
+
+func (txInfo *FullExitNftTxInfo) GetGas() (int64, int64, *big.Int) {
+	return txtypes.NilAccountIndex, txtypes.NilAssetId, nil
+}
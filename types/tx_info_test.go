@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTxInfo(t *testing.T) {
+	infos := map[int64]TxInfo{
+		TxTypeChangePubKey:     &ChangePubKeyTxInfo{AccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeDeposit:          &DepositTxInfo{AccountIndex: 2, AssetAmount: big.NewInt(100)},
+		TxTypeDepositNft:       &DepositNftTxInfo{AccountIndex: 2, NftIndex: 3},
+		TxTypeTransfer:         &TransferTxInfo{FromAccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeWithdraw:         &WithdrawTxInfo{FromAccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeCreateCollection: &CreateCollectionTxInfo{AccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeMintNft:          &MintNftTxInfo{CreatorAccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeTransferNft:      &TransferNftTxInfo{FromAccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeAtomicMatch:      &AtomicMatchTxInfo{AccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeCancelOffer:      &CancelOfferTxInfo{AccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeWithdrawNft:      &WithdrawNftTxInfo{AccountIndex: 2, Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10)},
+		TxTypeFullExit:         &FullExitTxInfo{AccountIndex: 2, AssetAmount: big.NewInt(100)},
+		TxTypeFullExitNft:      &FullExitNftTxInfo{AccountIndex: 2, NftIndex: 3},
+		TxTypeOffer:            &OfferTxInfo{AccountIndex: 2, AssetAmount: big.NewInt(100)},
+		TxTypeUpdateNFT:        &UpdateNftTxInfo{AccountIndex: 2, Nonce: 1, NftIndex: 3},
+	}
+	for txType := int64(TxTypeChangePubKey); txType <= TxTypeUpdateNFT; txType++ {
+		expected, ok := infos[txType]
+		assert.True(t, ok, "tx type %d", txType)
+		info, _ := json.Marshal(expected)
+		txInfo, err := DecodeTxInfo(&Tx{Type: txType, Info: string(info)})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, txInfo)
+		assert.Equal(t, int(txType), txInfo.GetTxType())
+		assert.Equal(t, int64(2), txInfo.GetAccountIndex())
+	}
+
+	// the txs sent by L1 have no nonce and gas
+	txInfo, _ := DecodeTxInfo(&Tx{Type: TxTypeDeposit, Info: `{"AccountIndex":2}`})
+	gasAccountIndex, _, gasFee := txInfo.GetGas()
+	assert.Equal(t, int64(-1), gasAccountIndex)
+	assert.Nil(t, gasFee)
+	assert.Equal(t, int64(-1), txInfo.GetNonce())
+	txInfo, _ = DecodeTxInfo(&Tx{Type: TxTypeTransfer, Info: `{"FromAccountIndex":2,"Nonce":1,"GasAccountIndex":1,"GasFeeAssetAmount":10}`})
+	gasAccountIndex, _, gasFee = txInfo.GetGas()
+	assert.Equal(t, int64(1), gasAccountIndex)
+	assert.Equal(t, big.NewInt(10), gasFee)
+	assert.Equal(t, int64(1), txInfo.GetNonce())
+
+	// the change pub key tx info keeps its fields and reads the infos of the txtypes tx
+	info, _ := json.Marshal(&txtypes.ChangePubKeyInfo{AccountIndex: 2, L1Address: "0x000000000000000000000000000000000000dEaD", Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10), ExpiredAt: 1000, L1Sig: "0x01"})
+	txInfo, _ = DecodeTxInfo(&Tx{Type: TxTypeChangePubKey, Info: string(info)})
+	assert.Equal(t, &ChangePubKeyTxInfo{AccountIndex: 2, L1Address: "0x000000000000000000000000000000000000dEaD", Nonce: 1, GasAccountIndex: 1, GasFeeAssetAmount: big.NewInt(10), ExpiredAt: 1000, L1Sig: "0x01"}, txInfo)
+	assert.Equal(t, int64(1000), txInfo.GetExpiredAt())
+
+	_, err := DecodeTxInfo(&Tx{Type: TxTypeEmpty, Info: "{}"})
+	assert.Error(t, err)
+	_, err = DecodeTxInfo(&Tx{Type: TxTypeUpdateNFT + 1, Info: "{}"})
+	assert.Error(t, err)
+	_, err = DecodeTxInfo(&Tx{Type: TxTypeTransfer, Info: "transfer"})
+	assert.Error(t, err)
+	_, err = DecodeTxInfo(nil)
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@ type (
 	WithdrawNftTxInfo      = txtypes.WithdrawNftTxInfo
 	WithdrawTxInfo         = txtypes.WithdrawTxInfo
 	OfferTxInfo            = txtypes.OfferTxInfo
+	UpdateNftTxInfo        = txtypes.UpdateNFTTxInfo
 )
 
 const (
@@ -138,6 +139,14 @@ type ChangePubKeyTxInfo struct {
 	L1Address    string
 	PubKeyX      []byte
 	PubKeyY      []byte
+
+	Nonce             int64
+	GasAccountIndex   int64
+	GasFeeAssetId     int64
+	GasFeeAssetAmount *big.Int
+	ExpiredAt         int64
+	Sig               []byte
+	L1Sig             string
 }
 
 func ParseChangePubKeyTxInfo(txInfoStr string) (txInfo *ChangePubKeyTxInfo, err error) {
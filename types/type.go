@@ -4,6 +4,16 @@ const (
 	CodeOK = 100
 )
 
+// Tx.Status values
+const (
+	TxStatusPending = iota
+	TxStatusExecuted
+	TxStatusPacked
+	TxStatusCommitted
+	TxStatusVerified
+	TxStatusFailed
+)
+
 type Result struct {
 	Code    uint32 `json:"code"`
 	Message string `json:"message"`
@@ -106,6 +116,9 @@ type Layer2BasicInfo struct {
 	YesterdayActiveUserCount  int64             `json:"yesterday_active_user_count"`
 	TodayActiveUserCount      int64             `json:"today_active_user_count"`
 	ContractAddresses         []ContractAddress `json:"contract_addresses"`
+	// L1SignModes are the schemes of L1 signatures accepted besides personal messages, an
+	// experimental field not served by the documented ZkBNB api
+	L1SignModes []string `json:"l1_sign_modes,omitempty"`
 }
 
 type GasFee struct {
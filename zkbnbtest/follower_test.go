@@ -0,0 +1,169 @@
+package zkbnbtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+func TestBlockFollower(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, _, _ := newTestClient(t, server)
+	server.SealBlock()
+
+	follower := client.NewBlockFollower(sdkClient, client.FollowFromHeight(1), client.FollowWithPollInterval(10*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var blocks []*types.Block
+	err := follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		blocks = append(blocks, block)
+		if block.Height == 1 {
+			txHash, err := sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e17)}, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), server.SealBlock().Height)
+			assert.NotNil(t, server.Tx(txHash))
+		} else {
+			cancel()
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, int64(types.TxTypeChangePubKey), blocks[0].Txs[0].Type)
+	assert.Equal(t, int64(types.TxTypeTransfer), blocks[1].Txs[0].Type)
+	checkpoint, err := follower.Checkpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), checkpoint.Height)
+}
+
+func TestBlockFollowerRollbacks(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, _, _ := newTestClient(t, server)
+	server.SealBlock()
+	for i := 0; i < 2; i++ {
+		_, err := sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e16)}, nil)
+		assert.NoError(t, err)
+		server.SealBlock()
+	}
+	// rollbacks from before the start are already applied
+	server.AddRollback(&types.Rollback{FromBlockHeight: 1})
+
+	var events []string
+	errStop := errors.New("stop")
+	store := client.NewMemoryCheckpointStore()
+	onRevert := func(ctx context.Context, revert *client.BlockRevert) error {
+		assert.Equal(t, int64(2), revert.Rollback.FromBlockHeight)
+		if revert.Block != nil {
+			assert.Equal(t, revert.Height, revert.Block.Height)
+			assert.Len(t, revert.Block.Txs, 1)
+		}
+		events = append(events, fmt.Sprintf("revert %d %t", revert.Height, revert.Block != nil))
+		if revert.Height == 2 && len(events) == 5 {
+			return errStop
+		}
+		return nil
+	}
+	follower := client.NewBlockFollower(sdkClient, client.FollowFromHeight(1), client.FollowWithCheckpointStore(store),
+		client.FollowWithPollInterval(10*time.Millisecond), client.FollowRollbacks(onRevert))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	onBlock := func(ctx context.Context, block *types.Block) error {
+		events = append(events, fmt.Sprintf("block %d %d", block.Height, len(block.Txs)))
+		if len(events) == 3 {
+			rollback := server.Rollback(2)
+			assert.NotEmpty(t, rollback.FromTxHash)
+			server.SealBlock()
+		}
+		return nil
+	}
+	err := follower.Run(ctx, onBlock)
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []string{"block 1 1", "block 2 1", "block 3 1", "revert 3 true", "revert 2 true"}, events)
+	// the failed revert is emitted again after a restart, without the block which is not known anymore
+	checkpoint, err := store.LoadCheckpoint(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), checkpoint.Height)
+
+	events = nil
+	follower = client.NewBlockFollower(sdkClient, client.FollowWithCheckpointStore(store),
+		client.FollowWithPollInterval(10*time.Millisecond), client.FollowRollbacks(onRevert))
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		events = append(events, fmt.Sprintf("block %d %d", block.Height, len(block.Txs)))
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"revert 2 false", "block 2 2"}, events)
+	checkpoint, err = store.LoadCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &client.Checkpoint{Height: 2, Commitment: checkpoint.Commitment, RollbackID: 2}, checkpoint)
+}
+
+func TestBlockFollowerResumeWithRollbacks(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, _, _ := newTestClient(t, server)
+	server.SealBlock()
+	_, err := sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e16)}, nil)
+	assert.NoError(t, err)
+	server.SealBlock()
+	server.AddRollback(&types.Rollback{FromBlockHeight: 1})
+
+	// the checkpoint is saved by a follower which does not watch the rollbacks
+	store := client.NewMemoryCheckpointStore()
+	follower := client.NewBlockFollower(sdkClient, client.FollowFromHeight(1), client.FollowWithCheckpointStore(store),
+		client.FollowWithPollInterval(10*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		if block.Height == 2 {
+			cancel()
+		}
+		return nil
+	})
+	cancel()
+	assert.ErrorIs(t, err, context.Canceled)
+	checkpoint, err := store.LoadCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), checkpoint.Height)
+	assert.Zero(t, checkpoint.RollbackID)
+
+	// resuming it with FollowRollbacks only reverts the blocks of the new rollbacks
+	_, err = sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e16)}, nil)
+	assert.NoError(t, err)
+	server.SealBlock()
+	var events []string
+	onRevert := func(ctx context.Context, revert *client.BlockRevert) error {
+		events = append(events, fmt.Sprintf("revert %d", revert.Height))
+		return nil
+	}
+	follower = client.NewBlockFollower(sdkClient, client.FollowWithCheckpointStore(store),
+		client.FollowWithPollInterval(10*time.Millisecond), client.FollowRollbacks(onRevert))
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = follower.Run(ctx, func(ctx context.Context, block *types.Block) error {
+		events = append(events, fmt.Sprintf("block %d", block.Height))
+		if len(events) == 1 {
+			assert.NotNil(t, server.Rollback(3))
+			server.SealBlock()
+		} else {
+			cancel()
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"block 3", "revert 3", "block 3"}, events)
+	checkpoint, err = store.LoadCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), checkpoint.RollbackID)
+}
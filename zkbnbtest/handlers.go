@@ -0,0 +1,592 @@
+package zkbnbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// The result codes of the errors returned by the server. They are specific to the server, the
+// client does not tell the errors of ZkBNB apart by their codes.
+const (
+	CodeInvalidParam       = 20001
+	CodeInvalidTxField     = 20002
+	CodeVerificationFailed = 20003
+	CodeInvalidTxType      = 20004
+	CodeAccountNotFound    = 21000
+	CodeInvalidNonce       = 21001
+	CodeBalanceNotEnough   = 21002
+	CodeBlockNotFound      = 23000
+	CodeTxNotFound         = 24000
+	CodeAssetNotFound      = 25000
+	CodeNftNotFound        = 27000
+	CodeNotFound           = 29404
+)
+
+type apiError struct {
+	code    uint32
+	message string
+}
+
+func newAPIError(code uint32, format string, args ...interface{}) *apiError {
+	return &apiError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	errInvalidParam    = newAPIError(CodeInvalidParam, "invalid param")
+	errAccountNotFound = newAPIError(CodeAccountNotFound, "account not found")
+	errBlockNotFound   = newAPIError(CodeBlockNotFound, "block not found")
+	errTxNotFound      = newAPIError(CodeTxNotFound, "tx not found")
+	errAssetNotFound   = newAPIError(CodeAssetNotFound, "asset not found")
+	errNftNotFound     = newAPIError(CodeNftNotFound, "nft not found")
+	errNotFound        = newAPIError(CodeNotFound, "not found")
+)
+
+// handle runs fn under the server lock and writes its result, or its error as a ZkBNB result.
+func (s *Server) handle(fn func(r *http.Request) (interface{}, *apiError)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		result, apiErr := fn(r)
+		var body []byte
+		if apiErr != nil {
+			body, _ = json.Marshal(&types.Result{Code: apiErr.code, Message: apiErr.message})
+		} else {
+			body = withResultCode(result)
+		}
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
+
+// withResultCode marshals result and adds the ok result code to it.
+func withResultCode(result interface{}) []byte {
+	body, _ := json.Marshal(result)
+	fields := make(map[string]json.RawMessage)
+	_ = json.Unmarshal(body, &fields)
+	fields["code"] = json.RawMessage(strconv.Itoa(types.CodeOK))
+	fields["message"] = json.RawMessage(`""`)
+	body, _ = json.Marshal(fields)
+	return body
+}
+
+func (s *Server) handlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/api/v1/currentHeight":     s.handle(s.currentHeight),
+		"/api/v1/layer2BasicInfo":   s.handle(s.layer2BasicInfo),
+		"/api/v1/getProtocolRate":   s.handle(s.getProtocolRate),
+		"/api/v1/search":            s.handle(s.search),
+		"/api/v1/account":           s.handle(s.getAccount),
+		"/api/v1/accounts":          s.handle(s.getAccounts),
+		"/api/v1/nextNonce":         s.handle(s.nextNonce),
+		"/api/v1/gasAccount":        s.handle(s.gasAccount),
+		"/api/v1/gasFee":            s.handle(s.getGasFee),
+		"/api/v1/gasFeeAssets":      s.handle(s.gasFeeAssets),
+		"/api/v1/asset":             s.handle(s.getAsset),
+		"/api/v1/assets":            s.handle(s.getAssets),
+		"/api/v1/block":             s.handle(s.getBlock),
+		"/api/v1/blocks":            s.handle(s.getBlocks),
+		"/api/v1/blockTxs":          s.handle(s.blockTxs),
+		"/api/v1/rollbacks":         s.handle(s.getRollbacks),
+		"/api/v1/tx":                s.handle(s.getTx),
+		"/api/v1/txs":               s.handle(s.getTxs),
+		"/api/v1/accountTxs":        s.handle(s.accountTxs),
+		"/api/v1/pendingTxs":        s.handle(s.pendingTxs),
+		"/api/v1/accountPendingTxs": s.handle(s.accountPendingTxs),
+		"/api/v1/executedTxs":       s.handle(s.executedTxs),
+		"/api/v1/accountNfts":       s.handle(s.accountNfts),
+		"/api/v1/GetNftByNftIndex":  s.handle(s.getNftByIndex),
+		"/api/v1/getNftByTxHash":    s.handle(s.getNftByTxHash),
+		"/api/v1/nftNextNonce":      s.handle(s.nftNextNonce),
+		"/api/v1/maxCollectionId":   s.handle(s.maxCollectionId),
+		"/api/v1/maxOfferId":        s.handle(s.maxOfferId),
+		"/api/v1/l2Signature":       s.handle(s.l2Signature),
+		"/api/v1/sendTx":            s.handle(s.sendTx),
+		"/api/v1/updateNftByIndex":  s.handle(s.updateNftByIndex),
+	}
+}
+
+func intParam(r *http.Request, name string) (int64, *apiError) {
+	value, err := strconv.ParseInt(r.FormValue(name), 10, 64)
+	if err != nil {
+		return 0, newAPIError(CodeInvalidParam, "invalid %s", name)
+	}
+	return value, nil
+}
+
+func pageParams(r *http.Request) (offset, limit int64, apiErr *apiError) {
+	if offset, apiErr = intParam(r, "offset"); apiErr != nil {
+		return 0, 0, apiErr
+	}
+	if limit, apiErr = intParam(r, "limit"); apiErr != nil {
+		return 0, 0, apiErr
+	}
+	if offset < 0 || limit < 1 || limit > 100 {
+		return 0, 0, errInvalidParam
+	}
+	return offset, limit, nil
+}
+
+func page[T any](items []T, offset, limit int64) []T {
+	if offset >= int64(len(items)) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > int64(len(items)) {
+		end = int64(len(items))
+	}
+	return items[offset:end]
+}
+
+// newestFirst returns the txs in reverse order, the way ZkBNB lists them.
+func newestFirst(txs []*types.EnrichedTx) []*types.Tx {
+	result := make([]*types.Tx, 0, len(txs))
+	for i := len(txs) - 1; i >= 0; i-- {
+		result = append(result, &txs[i].Tx)
+	}
+	return result
+}
+
+func plainTxs(txs []*types.EnrichedTx) []*types.Tx {
+	result := make([]*types.Tx, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, &tx.Tx)
+	}
+	return result
+}
+
+func (s *Server) currentHeight(r *http.Request) (interface{}, *apiError) {
+	return &types.CurrentHeight{Height: int64(len(s.blocks))}, nil
+}
+
+func (s *Server) layer2BasicInfo(r *http.Request) (interface{}, *apiError) {
+	info := &types.Layer2BasicInfo{TotalTransactionCount: int64(len(s.txs))}
+	if s.typedDataDomain != nil {
+		info.L1SignModes = []string{string(signer.L1SignModeTypedData)}
+		info.ContractAddresses = []types.ContractAddress{{Name: "ZkBNB", Address: s.zkbnbContract}}
+	}
+	for _, block := range s.blocks {
+		if block.Status >= types.TxStatusCommitted {
+			info.BlockCommitted = block.Height
+		}
+		if block.Status >= types.TxStatusVerified {
+			info.BlockVerified = block.Height
+		}
+	}
+	return info, nil
+}
+
+func (s *Server) getProtocolRate(r *http.Request) (interface{}, *apiError) {
+	return &types.ProtocolRate{ProtocolRate: strconv.FormatInt(s.protocolRate, 10)}, nil
+}
+
+// search is not supported by the fake, it answers not found to every keyword.
+func (s *Server) search(r *http.Request) (interface{}, *apiError) {
+	return nil, errNotFound
+}
+
+func (s *Server) findAccount(r *http.Request) (*Account, *apiError) {
+	switch r.FormValue("by") {
+	// the account endpoint takes index, the account txs and nfts endpoints take account_index
+	case "index", "account_index":
+		index, apiErr := intParam(r, "value")
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if account := s.account(index); account != nil {
+			return account, nil
+		}
+	case "l1_address":
+		if account, ok := s.accountByAddr[strings.ToLower(r.FormValue("value"))]; ok {
+			return account, nil
+		}
+	default:
+		return nil, errInvalidParam
+	}
+	return nil, errAccountNotFound
+}
+
+func (s *Server) getAccount(r *http.Request) (interface{}, *apiError) {
+	account, apiErr := s.findAccount(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	result := &types.Account{
+		Index:     account.Index,
+		L1Address: account.L1Address,
+		Pk:        account.Pk,
+		Nonce:     account.Nonce,
+	}
+	for _, asset := range s.assets {
+		if balance, ok := account.Balances[int64(asset.Id)]; ok {
+			result.Assets = append(result.Assets, &types.AccountAsset{
+				Id:      asset.Id,
+				Name:    asset.Name,
+				Balance: balance.String(),
+				Price:   asset.Price,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) getAccounts(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	result := &types.Accounts{Total: uint32(len(s.accounts)), Accounts: []*types.SimpleAccount{}}
+	for _, account := range page(s.accounts, offset, limit) {
+		result.Accounts = append(result.Accounts, &types.SimpleAccount{Index: account.Index, L1Address: account.L1Address, Pk: account.Pk})
+	}
+	return result, nil
+}
+
+func (s *Server) nextNonce(r *http.Request) (interface{}, *apiError) {
+	index, apiErr := intParam(r, "account_index")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	account := s.account(index)
+	if account == nil {
+		return nil, errAccountNotFound
+	}
+	return &types.NextNonce{Nonce: uint64(account.Nonce)}, nil
+}
+
+func (s *Server) gasAccount(r *http.Request) (interface{}, *apiError) {
+	account := s.account(GasAccountIndex)
+	return &types.GasAccount{Status: 1, Index: account.Index, L1Address: account.L1Address}, nil
+}
+
+func (s *Server) getGasFee(r *http.Request) (interface{}, *apiError) {
+	assetId, apiErr := intParam(r, "asset_id")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if asset := s.asset(assetId); asset == nil || asset.IsGasAsset == 0 {
+		return nil, errAssetNotFound
+	}
+	return &types.GasFee{GasFee: s.gasFee.String()}, nil
+}
+
+func (s *Server) gasFeeAssets(r *http.Request) (interface{}, *apiError) {
+	result := &types.GasFeeAssets{Assets: []types.Asset{}}
+	for _, asset := range s.assets {
+		if asset.IsGasAsset != 0 {
+			result.Assets = append(result.Assets, *asset)
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) asset(id int64) *types.Asset {
+	for _, asset := range s.assets {
+		if int64(asset.Id) == id {
+			return asset
+		}
+	}
+	return nil
+}
+
+func (s *Server) getAsset(r *http.Request) (interface{}, *apiError) {
+	switch r.FormValue("by") {
+	case "id":
+		id, apiErr := intParam(r, "value")
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if asset := s.asset(id); asset != nil {
+			return asset, nil
+		}
+	case "symbol":
+		for _, asset := range s.assets {
+			if asset.Symbol == r.FormValue("value") {
+				return asset, nil
+			}
+		}
+	default:
+		return nil, errInvalidParam
+	}
+	return nil, errAssetNotFound
+}
+
+func (s *Server) getAssets(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.Assets{Total: uint32(len(s.assets)), Assets: page(s.assets, offset, limit)}, nil
+}
+
+func (s *Server) getBlock(r *http.Request) (interface{}, *apiError) {
+	switch r.FormValue("by") {
+	case "height":
+		height, apiErr := intParam(r, "value")
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if height >= 1 && height <= int64(len(s.blocks)) {
+			return s.blocks[height-1], nil
+		}
+	case "commitment":
+		for _, block := range s.blocks {
+			if block.Commitment == r.FormValue("value") {
+				return block, nil
+			}
+		}
+	default:
+		return nil, errInvalidParam
+	}
+	return nil, errBlockNotFound
+}
+
+func (s *Server) getBlocks(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.Blocks{Total: uint32(len(s.blocks)), Blocks: page(s.blocks, offset, limit)}, nil
+}
+
+func (s *Server) blockTxs(r *http.Request) (interface{}, *apiError) {
+	if r.FormValue("by") != "block_height" {
+		return nil, errInvalidParam
+	}
+	height, apiErr := intParam(r, "value")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if height < 1 || height > int64(len(s.blocks)) {
+		return nil, errBlockNotFound
+	}
+	txs := s.blocks[height-1].Txs
+	return &types.Txs{Total: uint32(len(txs)), Txs: append([]*types.Tx{}, txs...)}, nil
+}
+
+func (s *Server) getRollbacks(r *http.Request) (interface{}, *apiError) {
+	fromHeight, apiErr := intParam(r, "from_block_height")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	rollbacks := []*types.Rollback{}
+	for _, rollback := range s.rollbacks {
+		if rollback.FromBlockHeight >= fromHeight {
+			rollbacks = append(rollbacks, rollback)
+		}
+	}
+	return &types.Rollbacks{Total: uint32(len(rollbacks)), Rollbacks: page(rollbacks, offset, limit)}, nil
+}
+
+func (s *Server) getTx(r *http.Request) (interface{}, *apiError) {
+	tx, ok := s.txByHash[r.FormValue("hash")]
+	if !ok {
+		return nil, errTxNotFound
+	}
+	return tx, nil
+}
+
+func (s *Server) getTxs(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.Txs{Total: uint32(len(s.txs)), Txs: page(newestFirst(s.txs), offset, limit)}, nil
+}
+
+func (s *Server) txsOf(account *Account, txs []*types.EnrichedTx, txTypes string) []*types.EnrichedTx {
+	var filter map[int64]bool
+	if txTypes != "" {
+		var list []int64
+		_ = json.Unmarshal([]byte(txTypes), &list)
+		filter = make(map[int64]bool)
+		for _, txType := range list {
+			filter[txType] = true
+		}
+	}
+	var result []*types.EnrichedTx
+	for _, tx := range txs {
+		if tx.AccountIndex != account.Index && tx.ToAccountIndex != account.Index {
+			continue
+		}
+		if filter != nil && !filter[tx.Type] {
+			continue
+		}
+		result = append(result, tx)
+	}
+	return result
+}
+
+func (s *Server) accountTxs(r *http.Request) (interface{}, *apiError) {
+	account, apiErr := s.findAccount(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	txs := s.txsOf(account, s.txs, r.FormValue("types"))
+	return &types.Txs{Total: uint32(len(txs)), Txs: page(newestFirst(txs), offset, limit)}, nil
+}
+
+func (s *Server) pendingTxs(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.Txs{Total: uint32(len(s.pending)), Txs: page(plainTxs(s.pending), offset, limit)}, nil
+}
+
+func (s *Server) accountPendingTxs(r *http.Request) (interface{}, *apiError) {
+	account, apiErr := s.findAccount(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	txs := s.txsOf(account, s.pending, r.FormValue("types"))
+	return &types.Txs{Total: uint32(len(txs)), Txs: plainTxs(txs)}, nil
+}
+
+func (s *Server) executedTxs(r *http.Request) (interface{}, *apiError) {
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	txs := s.pending
+	if fromHash := r.FormValue("from_hash"); fromHash != "" {
+		for i, tx := range txs {
+			if tx.Hash == fromHash {
+				txs = txs[i+1:]
+				break
+			}
+		}
+	}
+	return &types.Txs{Total: uint32(len(txs)), Txs: page(plainTxs(txs), offset, limit)}, nil
+}
+
+func (s *Server) accountNfts(r *http.Request) (interface{}, *apiError) {
+	if r.FormValue("by") != "account_index" {
+		return nil, errInvalidParam
+	}
+	index, apiErr := intParam(r, "value")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	offset, limit, apiErr := pageParams(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	nfts := []*types.Nft{}
+	for i := int64(0); i < s.nextNftIndex; i++ {
+		if nft, ok := s.nfts[i]; ok && nft.OwnerAccountIndex == index {
+			nfts = append(nfts, nft)
+		}
+	}
+	return &types.Nfts{Total: int64(len(nfts)), Nfts: page(nfts, offset, limit)}, nil
+}
+
+func (s *Server) getNftByIndex(r *http.Request) (interface{}, *apiError) {
+	index, apiErr := intParam(r, "nft_index")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	nft, ok := s.nfts[index]
+	if !ok {
+		return nil, errNftNotFound
+	}
+	return &types.NftEntity{Nft: nft}, nil
+}
+
+func (s *Server) getNftByTxHash(r *http.Request) (interface{}, *apiError) {
+	index, ok := s.nftByTxHash[r.FormValue("tx_hash")]
+	if !ok {
+		return nil, errNftNotFound
+	}
+	nft := s.nfts[index]
+	return &types.NftIndex{Index: nft.Index, IpfsId: nft.IpfsId, IpnsId: nft.IpnsId, Metadata: nft.Metadata, MutableAttributes: nft.MutableAttributes}, nil
+}
+
+func (s *Server) nftNextNonce(r *http.Request) (interface{}, *apiError) {
+	index, apiErr := intParam(r, "nft_index")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if _, ok := s.nfts[index]; !ok {
+		return nil, errNftNotFound
+	}
+	return &types.NextNonce{Nonce: uint64(s.nftNonces[index])}, nil
+}
+
+func (s *Server) maxCollectionId(r *http.Request) (interface{}, *apiError) {
+	index, apiErr := intParam(r, "account_index")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.MaxCollectionId{CollectionId: uint64(s.collections[index])}, nil
+}
+
+func (s *Server) maxOfferId(r *http.Request) (interface{}, *apiError) {
+	index, apiErr := intParam(r, "account_index")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.MaxOfferId{OfferId: uint64(s.offers[index])}, nil
+}
+
+func (s *Server) l2Signature(r *http.Request) (interface{}, *apiError) {
+	tx, apiErr := parseTx(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.SignBody{SignBody: tx.GetL1SignatureBody()}, nil
+}
+
+func (s *Server) sendTx(r *http.Request) (interface{}, *apiError) {
+	tx, apiErr := parseTx(r)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	hash, apiErr := s.applyTx(tx, r.FormValue("tx_info"), r.FormValue("l1_sign_mode"))
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &types.TxHash{TxHash: hash}, nil
+}
+
+func (s *Server) updateNftByIndex(r *http.Request) (interface{}, *apiError) {
+	tx := &txtypes.UpdateNFTTxInfo{}
+	if err := json.Unmarshal([]byte(r.FormValue("tx_info")), tx); err != nil {
+		return nil, newAPIError(CodeInvalidTxField, "invalid tx info: %s", err)
+	}
+	if err := tx.Validate(); err != nil {
+		return nil, newAPIError(CodeInvalidTxField, "%s", err)
+	}
+	nft, ok := s.nfts[tx.NftIndex]
+	if !ok {
+		return nil, errNftNotFound
+	}
+	owner := s.account(nft.OwnerAccountIndex)
+	if owner == nil || tx.AccountIndex != owner.Index {
+		return nil, newAPIError(CodeInvalidTxField, "not the owner of the nft")
+	}
+	if tx.Nonce != s.nftNonces[tx.NftIndex] {
+		return nil, newAPIError(CodeInvalidNonce, "invalid nonce")
+	}
+	if apiErr := s.verifyL1Sig(tx, owner.L1Address, r.FormValue("l1_sign_mode")); apiErr != nil {
+		return nil, apiErr
+	}
+	nft.MutableAttributes = tx.MutableAttributes
+	s.nftNonces[tx.NftIndex]++
+	return &types.Mutable{IpnsId: nft.IpnsId}, nil
+}
@@ -0,0 +1,229 @@
+package zkbnbtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/zkbnb-eth-rpc/core"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+)
+
+var (
+	// L1Balance is the BNB balance of the accounts funded by NewL1.
+	L1Balance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	// BEP20Balance is the balance of the accounts funded by NewL1 in the BEP20 token.
+	BEP20Balance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	// BEP20Address is the address of the BEP20 token deployed by NewL1.
+	BEP20Address = common.HexToAddress("0x00000000000000000000000000000000000B0020")
+)
+
+const l1GasLimit = 30000000
+
+var (
+	bep20CodeOnce sync.Once
+	bep20Code     []byte
+	bep20CodeErr  error
+)
+
+// bep20RuntimeCode returns the runtime code of the ERC20 binding of zkbnb-eth-rpc. It is
+// deployed to a throwaway chain, its constructor does not mint anything so NewL1 places the
+// code and the balances in the genesis instead.
+func bep20RuntimeCode() ([]byte, error) {
+	bep20CodeOnce.Do(func() {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			bep20CodeErr = err
+			return
+		}
+		backend := &simulatedBackend{backends.NewSimulatedBackend(
+			ethcore.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: L1Balance}}, l1GasLimit)}
+		defer backend.Close()
+		chainId, _ := backend.ChainID(context.Background())
+		opts, err := bind.NewKeyedTransactorWithChainID(key, chainId)
+		if err != nil {
+			bep20CodeErr = err
+			return
+		}
+		address, _, _, err := core.DeployERC20(opts, backend, "", "")
+		if err != nil {
+			bep20CodeErr = err
+			return
+		}
+		bep20Code, bep20CodeErr = backend.CodeAt(context.Background(), address, nil)
+	})
+	return bep20Code, bep20CodeErr
+}
+
+// bep20Genesis returns the genesis account of the BEP20 token, with the storage layout of
+// the OpenZeppelin ERC20: _balances, _allowances, _totalSupply, _name and _symbol.
+func bep20Genesis(holders []common.Address) (ethcore.GenesisAccount, error) {
+	code, err := bep20RuntimeCode()
+	if err != nil {
+		return ethcore.GenesisAccount{}, err
+	}
+	shortString := func(s string) common.Hash {
+		var h common.Hash
+		copy(h[:], s)
+		h[31] = byte(len(s) * 2)
+		return h
+	}
+	totalSupply := new(big.Int).Mul(BEP20Balance, big.NewInt(int64(len(holders))))
+	storage := map[common.Hash]common.Hash{
+		common.BigToHash(big.NewInt(2)): common.BigToHash(totalSupply),
+		common.BigToHash(big.NewInt(3)): shortString("Test BEP20"),
+		common.BigToHash(big.NewInt(4)): shortString("TBEP20"),
+	}
+	for _, holder := range holders {
+		slot := crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), 32), common.BigToHash(big.NewInt(0)).Bytes())
+		storage[slot] = common.BigToHash(BEP20Balance)
+	}
+	return ethcore.GenesisAccount{Code: code, Storage: storage, Balance: new(big.Int)}, nil
+}
+
+// L1 is a simulated L1 chain, backed by the go-ethereum simulated backend, with a stand-in
+// of the ZkBNB contract deployed. Every tx sent through it is mined in its own block right
+// away, so that L1 clients can be tested end to end without a node:
+//
+//	l1, err := zkbnbtest.NewL1(key)
+//	defer l1.Close()
+//	l1Client, err := l1.Client(key)
+//	tx, err := l1Client.DepositBNB(l1Address, amount)
+//	receipt, err := l1.Receipt(tx)
+type L1 struct {
+	backend *simulatedBackend
+	// ZkBNB is the address of the stand-in of the ZkBNB contract
+	ZkBNB common.Address
+	// Contract is bound to the stand-in, its filterer parses the emitted events
+	Contract *core.ZkBNB
+	// BEP20 is bound to a BEP20 token at BEP20Address, the funded accounts hold BEP20Balance of it
+	BEP20 *core.ERC20
+
+	deployer *ecdsa.PrivateKey
+}
+
+// simulatedBackend mines every tx right away and implements client.L1Backend.
+type simulatedBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b *simulatedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return b.Blockchain().Config().ChainID, nil
+}
+
+func (b *simulatedBackend) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+// NewL1 starts a simulated chain, funds the given keys with L1Balance BNB and BEP20Balance
+// of the BEP20 token and deploys the stand-in of the ZkBNB contract.
+//
+// The stand-in implements depositBNB, depositBEP20, depositNft, requestFullExit and
+// requestFullExitNft. They emit NewPriorityRequest, with the pubdata of the operation as
+// decoded by types.DecodeOnChainOperation, and Deposit or DepositNft like ZkBNB does. Tokens
+// and nfts are pulled with transferFrom and BEP20 tokens are listed, from asset id 1, on their
+// first deposit. The L2 info of deposited nfts is unknown to the stand-in and left zero.
+func NewL1(funded ...*ecdsa.PrivateKey) (*L1, error) {
+	deployer, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	alloc := ethcore.GenesisAlloc{crypto.PubkeyToAddress(deployer.PublicKey): {Balance: L1Balance}}
+	holders := make([]common.Address, 0, len(funded))
+	for _, key := range funded {
+		holder := crypto.PubkeyToAddress(key.PublicKey)
+		alloc[holder] = ethcore.GenesisAccount{Balance: L1Balance}
+		holders = append(holders, holder)
+	}
+	alloc[BEP20Address], err = bep20Genesis(holders)
+	if err != nil {
+		return nil, err
+	}
+	l1 := &L1{
+		backend:  &simulatedBackend{backends.NewSimulatedBackend(alloc, l1GasLimit)},
+		deployer: deployer,
+	}
+
+	opts, err := l1.Transactor(deployer)
+	if err != nil {
+		l1.Close()
+		return nil, err
+	}
+	parsed, err := core.ZkBNBMetaData.GetAbi()
+	if err != nil {
+		l1.Close()
+		return nil, err
+	}
+	l1.ZkBNB, _, _, err = bind.DeployContract(opts, *parsed, zkbnbStandInCode(), l1.backend)
+	if err != nil {
+		l1.Close()
+		return nil, err
+	}
+	l1.Contract, err = core.NewZkBNB(l1.ZkBNB, l1.backend)
+	if err != nil {
+		l1.Close()
+		return nil, err
+	}
+	l1.BEP20, err = core.NewERC20(BEP20Address, l1.backend)
+	if err != nil {
+		l1.Close()
+		return nil, err
+	}
+	return l1, nil
+}
+
+// Backend returns the underlying simulated backend, e.g. to adjust the time or to fork
+// the chain. Txs sent directly to it are only mined on Commit.
+func (l *L1) Backend() *backends.SimulatedBackend {
+	return l.backend.SimulatedBackend
+}
+
+// Client returns an L1 client of the simulated chain, signing with the given key.
+func (l *L1) Client(key *ecdsa.PrivateKey) (client.ZkBNBL1Client, error) {
+	l1Client, err := client.NewZkBNBL1ClientWithBackend(l.backend, l.ZkBNB.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := l1Client.SetPrivateKey(common.Bytes2Hex(crypto.FromECDSA(key))); err != nil {
+		return nil, err
+	}
+	return l1Client, nil
+}
+
+// Transactor returns transact options of the simulated chain for the given key.
+func (l *L1) Transactor(key *ecdsa.PrivateKey) (*bind.TransactOpts, error) {
+	chainId, _ := l.backend.ChainID(context.Background())
+	return bind.NewKeyedTransactorWithChainID(key, chainId)
+}
+
+// DeployERC721 deploys an nft contract whose tokens can be minted by anyone.
+func (l *L1) DeployERC721(name, symbol string) (common.Address, *core.ERC721, error) {
+	opts, err := l.Transactor(l.deployer)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	address, _, nft, err := core.DeployERC721(opts, l.backend, name, symbol)
+	return address, nft, err
+}
+
+// Receipt returns the receipt of a tx sent to the simulated chain.
+func (l *L1) Receipt(tx *ethtypes.Transaction) (*ethtypes.Receipt, error) {
+	return l.backend.TransactionReceipt(context.Background(), tx.Hash())
+}
+
+// Close stops the simulated chain.
+func (l *L1) Close() error {
+	return l.backend.Close()
+}
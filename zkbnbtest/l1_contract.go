@@ -0,0 +1,254 @@
+package zkbnbtest
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/bnb-chain/zkbnb-eth-rpc/core"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// PriorityExpirationBlocks is the number of L1 blocks after which a priority request
+// registered by the stand-in contract expires.
+const PriorityExpirationBlocks = 40320
+
+// storage slots of the stand-in contract, the asset ids are stored at the token address
+const (
+	slotSerialId   = 0
+	slotAssetCount = 1
+)
+
+// pubDataOffset is the memory offset of the pubdata of the NewPriorityRequest event, which
+// follows the head of the event and the length of the pubdata. The memory above it is only
+// written by field.
+const pubDataOffset = 0xc0
+
+// assembler builds EVM bytecode, jump targets are referenced by label and patched once
+// the whole program is known.
+type assembler struct {
+	code   []byte
+	labels map[string]int
+	refs   map[int]string
+}
+
+func newAssembler() *assembler {
+	return &assembler{labels: make(map[string]int), refs: make(map[int]string)}
+}
+
+func (a *assembler) op(ops ...vm.OpCode) *assembler {
+	for _, op := range ops {
+		a.code = append(a.code, byte(op))
+	}
+	return a
+}
+
+// push pushes the value with the shortest PUSH opcode that fits it.
+func (a *assembler) push(value *big.Int) *assembler {
+	b := value.Bytes()
+	if len(b) == 0 {
+		b = []byte{0}
+	}
+	a.code = append(a.code, byte(vm.PUSH1)+byte(len(b)-1))
+	a.code = append(a.code, b...)
+	return a
+}
+
+func (a *assembler) pushInt(value uint64) *assembler {
+	return a.push(new(big.Int).SetUint64(value))
+}
+
+func (a *assembler) pushLabel(label string) *assembler {
+	a.code = append(a.code, byte(vm.PUSH2))
+	a.refs[len(a.code)] = label
+	a.code = append(a.code, 0, 0)
+	return a
+}
+
+func (a *assembler) label(label string) *assembler {
+	a.labels[label] = len(a.code)
+	return a.op(vm.JUMPDEST)
+}
+
+// jumpIf jumps to label when the value on top of the stack is not zero.
+func (a *assembler) jumpIf(label string) *assembler {
+	return a.pushLabel(label).op(vm.JUMPI)
+}
+
+// arg pushes the i-th 32 bytes argument of the call.
+func (a *assembler) arg(i int) *assembler {
+	return a.pushInt(uint64(4 + 32*i)).op(vm.CALLDATALOAD)
+}
+
+// store pops the value on top of the stack into memory at offset.
+func (a *assembler) store(offset uint64) *assembler {
+	return a.pushInt(offset).op(vm.MSTORE)
+}
+
+// field pops the value on top of the stack into the pubdata, as a big endian field of size
+// bytes at offset. Each write zeroes the 32-size bytes following the field, so the fields are
+// written in order.
+func (a *assembler) field(offset, size uint64) *assembler {
+	return a.pushInt(8 * (32 - size)).op(vm.SHL).store(pubDataOffset + offset)
+}
+
+// log emits the event with the memory [0, size) as data.
+func (a *assembler) log(event abi.Event, size uint64) *assembler {
+	return a.push(event.ID.Big()).pushInt(size).pushInt(0).op(vm.LOG1)
+}
+
+func (a *assembler) bytecode() []byte {
+	code := append([]byte(nil), a.code...)
+	for at, label := range a.refs {
+		target, ok := a.labels[label]
+		if !ok {
+			panic("zkbnbtest: unknown label " + label)
+		}
+		binary.BigEndian.PutUint16(code[at:], uint16(target))
+	}
+	return code
+}
+
+// zkbnbStandInCode returns the creation code of a minimal stand-in of the ZkBNB contract.
+//
+// The stand-in implements depositBNB, depositBEP20, depositNft, requestFullExit and
+// requestFullExitNft with the abi of the ZkBNB contract. Each of them emits a
+// NewPriorityRequest event, with the pubdata chunk of the operation as encoded by
+// types.EncodeOnChainOperation, and the deposits emit Deposit or DepositNft. BEP20 tokens
+// and nfts are transferred to the contract with transferFrom, BEP20 tokens get an asset id,
+// starting from 1, on their first deposit and full exits of unlisted tokens revert. The
+// stand-in does not know the L2 info of the deposited nfts, only the L1 address is set in the
+// pubdata of their deposits. Any other call reverts.
+func zkbnbStandInCode() []byte {
+	parsed, err := core.ZkBNBMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	erc20, err := core.ERC20MetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	transferFrom := new(big.Int).Lsh(new(big.Int).SetBytes(erc20.Methods["transferFrom"].ID), 224)
+
+	a := newAssembler()
+	a.pushInt(4).op(vm.CALLDATASIZE, vm.LT).jumpIf("revert")
+	a.pushInt(0).op(vm.CALLDATALOAD).pushInt(224).op(vm.SHR)
+	methods := []string{"depositBNB", "depositBEP20", "depositNft", "requestFullExit", "requestFullExitNft"}
+	for _, method := range methods {
+		a.op(vm.DUP1).push(new(big.Int).SetBytes(parsed.Methods[method].ID)).op(vm.EQ).jumpIf(method)
+	}
+	a.label("revert").pushInt(0).op(vm.DUP1, vm.REVERT)
+
+	// priorityRequest emits NewPriorityRequest(caller, serialId, txType, pubData, expirationBlock),
+	// fields writes the fields of the operation following its tx type, the zero ones are left out
+	priorityRequest := func(txType int, fields func()) {
+		a.pushInt(uint64(txType)).field(0, 1)
+		fields()
+		a.pushInt(slotSerialId).op(vm.SLOAD, vm.DUP1).store(0x20)
+		a.pushInt(1).op(vm.ADD).pushInt(slotSerialId).op(vm.SSTORE)
+		a.op(vm.CALLER).store(0)
+		a.pushInt(uint64(txType)).store(0x40)
+		a.pushInt(0xa0).store(0x60)
+		a.pushInt(PriorityExpirationBlocks).op(vm.NUMBER, vm.ADD).store(0x80)
+		a.pushInt(types.PubDataBytesPerTx).store(0xa0)
+		a.log(parsed.Events["NewPriorityRequest"], pubDataOffset+(types.PubDataBytesPerTx+31)/32*32)
+	}
+	// pull calls token.transferFrom(caller, this, arg(amountArg)) and reverts on failure
+	pull := func(tokenArg, amountArg int, checkResult bool) {
+		a.arg(tokenArg).op(vm.EXTCODESIZE, vm.ISZERO).jumpIf("revert")
+		a.push(transferFrom).store(0)
+		a.op(vm.CALLER).store(0x04)
+		a.op(vm.ADDRESS).store(0x24)
+		a.arg(amountArg).store(0x44)
+		a.pushInt(0x20).pushInt(0).pushInt(0x64).pushInt(0).pushInt(0).arg(tokenArg).op(vm.GAS, vm.CALL)
+		a.op(vm.ISZERO).jumpIf("revert")
+		if checkResult {
+			// tokens returning nothing are accepted, tokens returning false are not
+			a.op(vm.RETURNDATASIZE, vm.ISZERO).jumpIf("pulled")
+			a.pushInt(0).op(vm.MLOAD, vm.ISZERO).jumpIf("revert")
+			a.label("pulled")
+		}
+	}
+	nonPayable := func() {
+		a.op(vm.CALLVALUE).jumpIf("revert")
+	}
+
+	// depositBNB(address _to)
+	a.label("depositBNB")
+	a.op(vm.CALLVALUE, vm.ISZERO).jumpIf("revert")
+	priorityRequest(types.TxTypeDeposit, func() {
+		a.arg(0).field(5, 20)
+		a.op(vm.CALLVALUE).field(27, 16)
+	})
+	a.pushInt(0).store(0)
+	a.arg(0).store(0x20)
+	a.op(vm.CALLVALUE).store(0x40)
+	a.log(parsed.Events["Deposit"], 0x60).op(vm.STOP)
+
+	// depositBEP20(address _token, uint104 _amount, address _to)
+	a.label("depositBEP20")
+	nonPayable()
+	a.arg(1).op(vm.ISZERO).jumpIf("revert")
+	pull(0, 1, true)
+	a.arg(0).op(vm.SLOAD, vm.DUP1).jumpIf("listed")
+	a.op(vm.POP).pushInt(slotAssetCount).op(vm.SLOAD).pushInt(1).op(vm.ADD, vm.DUP1).pushInt(slotAssetCount).op(vm.SSTORE)
+	a.op(vm.DUP1).arg(0).op(vm.SSTORE)
+	a.label("listed")
+	priorityRequest(types.TxTypeDeposit, func() {
+		a.arg(2).field(5, 20)
+		a.op(vm.DUP1).field(25, 2)
+		a.arg(1).field(27, 16)
+	})
+	a.store(0)
+	a.arg(2).store(0x20)
+	a.arg(1).store(0x40)
+	a.log(parsed.Events["Deposit"], 0x60).op(vm.STOP)
+
+	// depositNft(address _to, address _nftL1Address, uint256 _nftL1TokenId)
+	a.label("depositNft")
+	nonPayable()
+	pull(1, 2, false)
+	priorityRequest(types.TxTypeDepositNft, func() {
+		a.arg(0).field(18, 20)
+	})
+	a.arg(0).store(0)
+	a.pushInt(0).store(0x20)
+	a.arg(1).store(0x40)
+	a.arg(2).store(0x60)
+	a.pushInt(0).store(0x80)
+	a.log(parsed.Events["DepositNft"], 0xa0).op(vm.STOP)
+
+	// requestFullExit(uint32 _accountIndex, address _asset)
+	a.label("requestFullExit")
+	nonPayable()
+	// BNB is asset 0, BEP20 tokens have to be listed
+	a.arg(1).op(vm.DUP1, vm.ISZERO).jumpIf("fullExitAsset")
+	a.op(vm.SLOAD, vm.DUP1, vm.ISZERO).jumpIf("revert")
+	a.label("fullExitAsset")
+	priorityRequest(types.TxTypeFullExit, func() {
+		a.arg(0).field(1, 4)
+		a.op(vm.DUP1).field(5, 2)
+		a.op(vm.CALLER).field(23, 20)
+	})
+	a.op(vm.STOP)
+
+	// requestFullExitNft(uint32 _accountIndex, uint32 _nftIndex)
+	a.label("requestFullExitNft")
+	nonPayable()
+	priorityRequest(types.TxTypeFullExitNft, func() {
+		a.arg(0).field(1, 4)
+		a.arg(1).field(11, 5)
+		a.op(vm.CALLER).field(18, 20)
+	})
+	a.op(vm.STOP)
+
+	runtime := a.bytecode()
+	// the creation code copies the runtime code, which follows it, to memory and returns it
+	const creationSize = 12
+	creation := newAssembler()
+	creation.code = append(creation.code, byte(vm.PUSH2), byte(len(runtime)>>8), byte(len(runtime)))
+	creation.op(vm.DUP1).pushInt(creationSize).pushInt(0).op(vm.CODECOPY).pushInt(0).op(vm.RETURN)
+	return append(creation.code, runtime...)
+}
@@ -0,0 +1,175 @@
+package zkbnbtest_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/zkbnb-eth-rpc/core"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+// zkbnbLogs returns the logs emitted by the ZkBNB stand-in, leaving out the token transfers.
+func zkbnbLogs(t *testing.T, l1 *zkbnbtest.L1, receipt *ethtypes.Receipt) []ethtypes.Log {
+	assert.Equal(t, ethtypes.ReceiptStatusSuccessful, receipt.Status)
+	var logs []ethtypes.Log
+	for _, log := range receipt.Logs {
+		if log.Address == l1.ZkBNB {
+			logs = append(logs, *log)
+		}
+	}
+	return logs
+}
+
+func priorityRequest(t *testing.T, l1 *zkbnbtest.L1, receipt *ethtypes.Receipt) *core.ZkBNBNewPriorityRequest {
+	request, err := l1.Contract.ParseNewPriorityRequest(zkbnbLogs(t, l1, receipt)[0])
+	assert.NoError(t, err)
+	assert.Equal(t, receipt.BlockNumber.Uint64()+zkbnbtest.PriorityExpirationBlocks, request.ExpirationBlock.Uint64())
+	assert.Len(t, request.PubData, types.PubDataBytesPerTx)
+	return request
+}
+
+// operation decodes the pubdata of the priority request.
+func operation(t *testing.T, request *core.ZkBNBNewPriorityRequest) types.OnChainOperation {
+	op, err := types.DecodeOnChainOperation(request.PubData)
+	assert.NoError(t, err)
+	assert.Equal(t, int(request.TxType), op.GetTxType())
+	return op
+}
+
+func TestL1Deposits(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	l1, err := zkbnbtest.NewL1(key)
+	assert.NoError(t, err)
+	defer l1.Close()
+	l1Client, err := l1.Client(key)
+	assert.NoError(t, err)
+	to := "0x000000000000000000000000000000000000dEaD"
+
+	// BNB
+	tx, err := l1Client.DepositBNB(to, big.NewInt(1e18))
+	assert.NoError(t, err)
+	receipt, err := l1.Receipt(tx)
+	assert.NoError(t, err)
+	request := priorityRequest(t, l1, receipt)
+	assert.Equal(t, sender, request.Sender)
+	assert.Equal(t, uint64(0), request.SerialId)
+	assert.Equal(t, uint8(types.TxTypeDeposit), request.TxType)
+	assert.Equal(t, &types.DepositOperation{L1Address: common.HexToAddress(to).Hex(), AssetAmount: big.NewInt(1e18)}, operation(t, request))
+	deposit, err := l1.Contract.ParseDeposit(zkbnbLogs(t, l1, receipt)[1])
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), deposit.AssetId)
+	assert.Equal(t, common.HexToAddress(to), deposit.To)
+	assert.Equal(t, big.NewInt(1e18), deposit.Amount)
+	balance, err := l1.Backend().BalanceAt(context.Background(), l1.ZkBNB, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1e18), balance)
+	_, err = l1Client.DepositBNB(to, big.NewInt(0))
+	assert.Error(t, err)
+
+	// BEP20, pulled with transferFrom once approved
+	tokenAddress, token := zkbnbtest.BEP20Address, l1.BEP20
+	balance, err = token.BalanceOf(nil, sender)
+	assert.NoError(t, err)
+	assert.Equal(t, zkbnbtest.BEP20Balance, balance)
+	_, err = l1Client.DepositBEP20(tokenAddress, to, big.NewInt(100))
+	assert.Error(t, err)
+	opts, err := l1.Transactor(key)
+	assert.NoError(t, err)
+	_, err = token.Approve(opts, l1.ZkBNB, big.NewInt(1000))
+	assert.NoError(t, err)
+	tx, err = l1Client.DepositBEP20(tokenAddress, to, big.NewInt(100))
+	assert.NoError(t, err)
+	receipt, err = l1.Receipt(tx)
+	assert.NoError(t, err)
+	request = priorityRequest(t, l1, receipt)
+	assert.Equal(t, uint64(1), request.SerialId)
+	assert.Equal(t, &types.DepositOperation{L1Address: common.HexToAddress(to).Hex(), AssetId: 1, AssetAmount: big.NewInt(100)}, operation(t, request))
+	deposit, err = l1.Contract.ParseDeposit(zkbnbLogs(t, l1, receipt)[1])
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), deposit.AssetId)
+	assert.Equal(t, big.NewInt(100), deposit.Amount)
+	locked, err := token.BalanceOf(nil, l1.ZkBNB)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), locked)
+	// listed tokens can be exited with their asset id
+	tx, err = l1Client.RequestFullExit(2, tokenAddress)
+	assert.NoError(t, err)
+	receipt, err = l1.Receipt(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), operation(t, priorityRequest(t, l1, receipt)).(*types.FullExitOperation).AssetId)
+
+	// nft
+	nftAddress, nft, err := l1.DeployERC721("Test Nft", "TN")
+	assert.NoError(t, err)
+	_, err = nft.Mint(opts, sender, big.NewInt(1))
+	assert.NoError(t, err)
+	_, err = nft.SetApprovalForAll(opts, l1.ZkBNB, true)
+	assert.NoError(t, err)
+	tokenId := big.NewInt(0)
+	owner, err := nft.OwnerOf(nil, tokenId)
+	assert.NoError(t, err)
+	assert.Equal(t, sender, owner)
+	tx, err = l1Client.DepositNft(nftAddress, to, tokenId)
+	assert.NoError(t, err)
+	receipt, err = l1.Receipt(tx)
+	assert.NoError(t, err)
+	request = priorityRequest(t, l1, receipt)
+	assert.Equal(t, uint8(types.TxTypeDepositNft), request.TxType)
+	depositNftOp := operation(t, request).(*types.DepositNftOperation)
+	assert.Equal(t, common.HexToAddress(to).Hex(), depositNftOp.L1Address)
+	assert.Zero(t, depositNftOp.NftIndex)
+	depositNft, err := l1.Contract.ParseDepositNft(zkbnbLogs(t, l1, receipt)[1])
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(to), depositNft.To)
+	assert.Equal(t, nftAddress, depositNft.TokenAddress)
+	assert.Zero(t, tokenId.Cmp(depositNft.NftTokenId))
+	owner, err = nft.OwnerOf(nil, tokenId)
+	assert.NoError(t, err)
+	assert.Equal(t, l1.ZkBNB, owner)
+}
+
+func TestL1FullExit(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l1, err := zkbnbtest.NewL1(key)
+	assert.NoError(t, err)
+	defer l1.Close()
+	l1Client, err := l1.Client(key)
+	assert.NoError(t, err)
+
+	sender := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	tx, err := l1Client.RequestFullExit(2, common.Address{})
+	assert.NoError(t, err)
+	receipt, err := l1.Receipt(tx)
+	assert.NoError(t, err)
+	request := priorityRequest(t, l1, receipt)
+	assert.Equal(t, uint8(types.TxTypeFullExit), request.TxType)
+	fullExit := operation(t, request).(*types.FullExitOperation)
+	assert.Equal(t, int64(2), fullExit.AccountIndex)
+	assert.Equal(t, int64(0), fullExit.AssetId)
+	assert.Zero(t, fullExit.AssetAmount.Sign())
+	assert.Equal(t, sender, fullExit.L1Address)
+	// tokens which were never deposited are not listed
+	_, err = l1Client.RequestFullExit(2, zkbnbtest.BEP20Address)
+	assert.Error(t, err)
+
+	tx, err = l1Client.RequestFullExitNft(2, 7)
+	assert.NoError(t, err)
+	receipt, err = l1.Receipt(tx)
+	assert.NoError(t, err)
+	request = priorityRequest(t, l1, receipt)
+	assert.Equal(t, uint8(types.TxTypeFullExitNft), request.TxType)
+	assert.Equal(t, uint64(1), request.SerialId)
+	fullExitNft := operation(t, request).(*types.FullExitNftOperation)
+	assert.Equal(t, int64(2), fullExitNft.AccountIndex)
+	assert.Equal(t, int64(7), fullExitNft.NftIndex)
+	assert.Equal(t, sender, fullExitNft.L1Address)
+}
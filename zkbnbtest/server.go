@@ -0,0 +1,360 @@
+// Package zkbnbtest provides an in-process fake of the ZkBNB api for tests.
+//
+// The fake keeps accounts, balances, nonces, nfts, txs and blocks in memory. Submitted txs
+// are validated, their signatures are checked against the public key of the account and
+// their effects on balances and nfts are applied, so that a client can be tested end to end
+// without a ZkBNB node:
+//
+//	server := zkbnbtest.NewServer()
+//	defer server.Close()
+//	index := server.AddAccount(l1Address, "")
+//	server.SetBalance(index, 0, big.NewInt(1e18))
+//	client, err := client.NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainId)
+package zkbnbtest
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const (
+	// TreasuryAccountIndex is the index of the treasury account created by NewServer
+	TreasuryAccountIndex = 0
+	// GasAccountIndex is the index of the gas account created by NewServer, it collects the gas fees
+	GasAccountIndex = 1
+)
+
+// DefaultGasFee is the gas fee charged for every tx unless changed with SetGasFee.
+var DefaultGasFee = big.NewInt(1000000000000)
+
+// Account is the state of an L2 account kept by the fake.
+type Account struct {
+	Index     int64
+	L1Address string
+	// Pk is the hex encoded compressed EdDSA public key, empty until the account changed its public key
+	Pk       string
+	Nonce    int64
+	Balances map[int64]*big.Int
+}
+
+// Server is a fake ZkBNB api server. Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	accounts      []*Account
+	accountByAddr map[string]*Account
+	assets        []*types.Asset
+	nfts          map[int64]*types.Nft
+	nftNonces     map[int64]int64
+	nftByTxHash   map[string]int64
+	nextNftIndex  int64
+	collections   map[int64]int64
+	offers        map[int64]int64
+	txs           []*types.EnrichedTx
+	txByHash      map[string]*types.EnrichedTx
+	pending       []*types.EnrichedTx
+	blocks        []*types.Block
+	rollbacks     []*types.Rollback
+	gasFee        *big.Int
+	protocolRate  int64
+	// typedDataDomain is set once EIP-712 typed data signatures are accepted
+	typedDataDomain *apitypes.TypedDataDomain
+	zkbnbContract   string
+}
+
+// NewServer starts a fake server with a treasury account, a gas account and BNB as asset 0.
+func NewServer() *Server {
+	s := &Server{
+		accountByAddr: make(map[string]*Account),
+		nfts:          make(map[int64]*types.Nft),
+		nftNonces:     make(map[int64]int64),
+		nftByTxHash:   make(map[string]int64),
+		collections:   make(map[int64]int64),
+		offers:        make(map[int64]int64),
+		txByHash:      make(map[string]*types.EnrichedTx),
+		gasFee:        new(big.Int).Set(DefaultGasFee),
+		protocolRate:  200,
+	}
+	s.AddAccount("0x0000000000000000000000000000000000000000", "")
+	s.AddAccount("0x0000000000000000000000000000000000000001", "")
+	s.AddAsset(&types.Asset{Id: 0, Name: "BNB", Decimals: 18, Symbol: "BNB", Price: "1", IsGasAsset: 1})
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// AddAccount registers an L2 account and returns its index. pubKey is the hex encoded
+// compressed EdDSA public key, it can be left empty and set later with a ChangePubKey tx.
+func (s *Server) AddAccount(l1Address string, pubKey string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addAccount(l1Address, pubKey).Index
+}
+
+func (s *Server) addAccount(l1Address string, pubKey string) *Account {
+	account := &Account{
+		Index:     int64(len(s.accounts)),
+		L1Address: l1Address,
+		Pk:        pubKey,
+		Balances:  make(map[int64]*big.Int),
+	}
+	s.accounts = append(s.accounts, account)
+	s.accountByAddr[strings.ToLower(l1Address)] = account
+	return account
+}
+
+// Account returns a copy of the account state, nil if the account does not exist.
+func (s *Server) Account(accountIndex int64) *Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account := s.account(accountIndex)
+	if account == nil {
+		return nil
+	}
+	cp := *account
+	cp.Balances = make(map[int64]*big.Int, len(account.Balances))
+	for assetId, balance := range account.Balances {
+		cp.Balances[assetId] = new(big.Int).Set(balance)
+	}
+	return &cp
+}
+
+func (s *Server) account(accountIndex int64) *Account {
+	if accountIndex < 0 || accountIndex >= int64(len(s.accounts)) {
+		return nil
+	}
+	return s.accounts[accountIndex]
+}
+
+// SetBalance sets the balance of an account for an asset.
+func (s *Server) SetBalance(accountIndex, assetId int64, amount *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if account := s.account(accountIndex); account != nil {
+		account.Balances[assetId] = new(big.Int).Set(amount)
+	}
+}
+
+// Balance returns the balance of an account for an asset.
+func (s *Server) Balance(accountIndex, assetId int64) *big.Int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account := s.account(accountIndex)
+	if account == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(account.balance(assetId))
+}
+
+func (a *Account) balance(assetId int64) *big.Int {
+	balance, ok := a.Balances[assetId]
+	if !ok {
+		balance = new(big.Int)
+		a.Balances[assetId] = balance
+	}
+	return balance
+}
+
+// AddAsset registers an asset, an asset with the same id is replaced.
+func (s *Server) AddAsset(asset *types.Asset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, a := range s.assets {
+		if a.Id == asset.Id {
+			s.assets[i] = asset
+			return
+		}
+	}
+	s.assets = append(s.assets, asset)
+}
+
+// AddNft registers an nft owned by the given account and returns its index.
+func (s *Server) AddNft(nft *types.Nft) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nft.Index = s.nextNftIndex
+	s.nextNftIndex++
+	if owner := s.account(nft.OwnerAccountIndex); owner != nil {
+		nft.OwnerL1Address = owner.L1Address
+	}
+	s.nfts[nft.Index] = nft
+	return nft.Index
+}
+
+// Nft returns a copy of the nft, nil if it does not exist.
+func (s *Server) Nft(nftIndex int64) *types.Nft {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nft, ok := s.nfts[nftIndex]
+	if !ok {
+		return nil
+	}
+	cp := *nft
+	return &cp
+}
+
+// SetGasFee sets the gas fee returned by /api/v1/gasFee.
+func (s *Server) SetGasFee(fee *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gasFee = new(big.Int).Set(fee)
+}
+
+// EnableTypedDataSigning makes the fake accept L1 signatures of EIP-712 typed data for
+// the txs sent with the l1 sign mode signer.L1SignModeTypedData, and advertise it in the
+// layer 2 basic info along with the ZkBNB contract address.
+func (s *Server) EnableTypedDataSigning(chainId uint64, zkbnbContract string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	domain := txutils.TypedDataDomain(chainId, zkbnbContract)
+	s.typedDataDomain = &domain
+	s.zkbnbContract = zkbnbContract
+}
+
+// AddRollback records a rollback returned by /api/v1/rollbacks.
+func (s *Server) AddRollback(rollback *types.Rollback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rollback.ID = uint(len(s.rollbacks) + 1)
+	s.rollbacks = append(s.rollbacks, rollback)
+}
+
+// Rollback drops the blocks from the given height, their txs go back to the pending txs and
+// are packed again by the next SealBlock. The rollback is recorded for /api/v1/rollbacks and
+// returned, nil when there is no block at that height.
+func (s *Server) Rollback(fromBlockHeight int64) *types.Rollback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fromBlockHeight < 1 || fromBlockHeight > int64(len(s.blocks)) {
+		return nil
+	}
+	var reverted []*types.EnrichedTx
+	for _, block := range s.blocks[fromBlockHeight-1:] {
+		for _, tx := range block.Txs {
+			enriched := s.txByHash[tx.Hash]
+			enriched.Status = types.TxStatusExecuted
+			enriched.BlockHeight = 0
+			enriched.Index = 0
+			enriched.CommittedAt = 0
+			enriched.VerifiedAt = 0
+			reverted = append(reverted, enriched)
+		}
+	}
+	s.blocks = s.blocks[:fromBlockHeight-1]
+	s.pending = append(reverted, s.pending...)
+
+	rollback := &types.Rollback{
+		FromBlockHeight: fromBlockHeight,
+		ID:              uint(len(s.rollbacks) + 1),
+		CreatedAt:       time.Now().Unix(),
+	}
+	if len(reverted) > 0 {
+		rollback.FromTxHash = reverted[0].Hash
+	}
+	s.rollbacks = append(s.rollbacks, rollback)
+	cp := *rollback
+	return &cp
+}
+
+// SealBlock packs the executed txs into a new block and returns it. Txs submitted with
+// /api/v1/sendTx stay executed but pending until a block is sealed. The deposits are counted
+// in the priority operations of the block and the withdrawals are encoded in its pending
+// on-chain operations pubdata.
+func (s *Server) SealBlock() *types.Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block := &types.Block{
+		Height:     int64(len(s.blocks) + 1),
+		Commitment: fmt.Sprintf("%064x", len(s.blocks)+1),
+		Status:     types.TxStatusPacked,
+	}
+	var ops []types.OnChainOperation
+	for i, tx := range s.pending {
+		tx.Status = types.TxStatusPacked
+		tx.BlockHeight = block.Height
+		tx.Index = int64(i)
+		block.Txs = append(block.Txs, &tx.Tx)
+		switch tx.Type {
+		case types.TxTypeDeposit:
+			block.PriorityOperations++
+		case types.TxTypeWithdraw:
+			txInfo, _ := types.DecodeTxInfo(&tx.Tx)
+			ops = append(ops, withdrawOperation(txInfo.(*types.WithdrawTxInfo)))
+		}
+	}
+	pubData, err := types.EncodePendingOnChainOperations(ops...)
+	if err != nil {
+		// applyTx only accepts the withdrawals it can encode
+		panic(fmt.Sprintf("zkbnbtest: encode the pubdata of block %d: %v", block.Height, err))
+	}
+	block.PendingOnChainOperationsPubData = pubData
+	block.Size = uint16(len(block.Txs))
+	s.pending = nil
+	s.blocks = append(s.blocks, block)
+	cp := *block
+	return &cp
+}
+
+// CommitBlocks marks all sealed blocks and their txs as committed on L1.
+func (s *Server) CommitBlocks() {
+	s.setBlocksStatus(types.TxStatusCommitted)
+}
+
+// VerifyBlocks marks all sealed blocks and their txs as verified on L1.
+func (s *Server) VerifyBlocks() {
+	s.setBlocksStatus(types.TxStatusVerified)
+}
+
+func (s *Server) setBlocksStatus(status int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().Unix()
+	for _, block := range s.blocks {
+		if block.Status >= status {
+			continue
+		}
+		block.Status = status
+		if block.CommittedAt == 0 {
+			block.CommittedAt = now
+		}
+		if status == types.TxStatusVerified {
+			block.VerifiedAt = now
+		}
+		for _, tx := range block.Txs {
+			enriched := s.txByHash[tx.Hash]
+			enriched.Status = status
+			enriched.CommittedAt = block.CommittedAt
+			enriched.VerifiedAt = block.VerifiedAt
+		}
+	}
+}
+
+// Tx returns a copy of the tx, nil if it does not exist.
+func (s *Server) Tx(hash string) *types.EnrichedTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.txByHash[hash]
+	if !ok {
+		return nil
+	}
+	cp := *tx
+	return &cp
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	for path, handler := range s.handlers() {
+		mux.HandleFunc(path, handler)
+	}
+	return mux
+}
@@ -0,0 +1,144 @@
+package zkbnbtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+const chainId = 97
+
+func newTestClient(t *testing.T, server *zkbnbtest.Server) (client.ZkBNBClient, int64, string) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	index := server.AddAccount(l1Address, "")
+	server.SetBalance(index, 0, big.NewInt(1e18))
+
+	sdkClient, err := client.NewZkBNBClientWithPrivateKey(server.URL, common.Bytes2Hex(crypto.FromECDSA(key)), chainId)
+	assert.NoError(t, err)
+	pubKey := sdkClient.KeyManager().PubKeyPoint()
+	_, err = sdkClient.ChangePubKey(&types.ChangePubKeyReq{L1Address: l1Address, PubKeyX: pubKey[0], PubKeyY: pubKey[1]}, nil)
+	assert.NoError(t, err)
+	return sdkClient, index, l1Address
+}
+
+func TestServerTransfer(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, index, l1Address := newTestClient(t, server)
+	assert.Equal(t, common.Bytes2Hex(sdkClient.KeyManager().PubKey().Bytes()), server.Account(index).Pk)
+
+	to := "0x000000000000000000000000000000000000dEaD"
+	txHash, err := sdkClient.Transfer(&types.TransferTxReq{To: to, AssetAmount: big.NewInt(1e17)}, nil)
+	assert.NoError(t, err)
+
+	fees := new(big.Int).Mul(zkbnbtest.DefaultGasFee, big.NewInt(2))
+	expected := new(big.Int).Sub(big.NewInt(9e17), fees)
+	assert.Equal(t, expected, server.Balance(index, 0))
+	assert.Equal(t, fees, server.Balance(zkbnbtest.GasAccountIndex, 0))
+	receiver, err := sdkClient.GetAccountByL1Address(to)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000000000000", receiver.Assets[0].Balance)
+
+	nonce, err := sdkClient.GetNextNonce(index)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), nonce)
+	_, pending, err := sdkClient.GetPendingTxsByL1Address(l1Address)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	server.SealBlock()
+	server.CommitBlocks()
+	tx, block, err := sdkClient.WaitForTx(context.Background(), txHash, client.TxStageCommitted, client.WaitTxWithTimeout(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), block.Height)
+	assert.Equal(t, index, tx.AccountIndex)
+
+	// balances are checked
+	_, err = sdkClient.Transfer(&types.TransferTxReq{To: to, AssetAmount: big.NewInt(1e18)}, nil)
+	assert.ErrorIs(t, err, &client.APIError{Code: zkbnbtest.CodeBalanceNotEnough})
+
+	// so are nonces and signatures
+	_, err = sdkClient.Transfer(&types.TransferTxReq{To: to, AssetAmount: big.NewInt(1)}, &types.TransactOpts{Nonce: 7})
+	assert.ErrorIs(t, err, &client.APIError{Code: zkbnbtest.CodeInvalidNonce})
+	other, _, _ := newTestClient(t, server)
+	_, err = other.Transfer(&types.TransferTxReq{To: to, AssetAmount: big.NewInt(1)}, &types.TransactOpts{FromAccountIndex: index})
+	assert.ErrorIs(t, err, &client.APIError{Code: zkbnbtest.CodeVerificationFailed})
+}
+
+func TestServerNft(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, index, l1Address := newTestClient(t, server)
+
+	_, err := sdkClient.CreateCollection(&types.CreateCollectionTxReq{Name: "collection", Introduction: "test"}, nil)
+	assert.NoError(t, err)
+	collection, err := sdkClient.GetMaxCollectionId(index)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), collection.CollectionId)
+
+	txHash, err := sdkClient.MintNft(&types.MintNftTxReq{To: l1Address, NftCollectionId: 1, MetaData: "{}"}, nil)
+	assert.NoError(t, err)
+	nftIndex, err := sdkClient.GetNftByTxHash(txHash)
+	assert.NoError(t, err)
+
+	to := "0x000000000000000000000000000000000000dEaD"
+	_, err = sdkClient.TransferNft(&types.TransferNftTxReq{To: to, NftIndex: nftIndex.Index}, nil)
+	assert.NoError(t, err)
+	nft, err := sdkClient.GetNftByNftIndex(nftIndex.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, to, nft.OwnerL1Address)
+
+	nfts, err := sdkClient.GetNftsByAccountIndex(index, 0, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, nfts.Total)
+	_, err = sdkClient.TransferNft(&types.TransferNftTxReq{To: to, NftIndex: nftIndex.Index}, nil)
+	assert.Error(t, err)
+}
+
+func TestServerOnChainOperations(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, index, l1Address := newTestClient(t, server)
+	server.Deposit(l1Address, 0, big.NewInt(1e17))
+
+	// withdrawals whose gas fee can't be packed into the pubdata are rejected
+	to := "0x000000000000000000000000000000000000dEaD"
+	txInfo, _ := json.Marshal(&types.WithdrawTxInfo{
+		FromAccountIndex:  index,
+		GasAccountIndex:   zkbnbtest.GasAccountIndex,
+		GasFeeAssetAmount: new(big.Int).Add(zkbnbtest.DefaultGasFee, big.NewInt(1)),
+		AssetAmount:       big.NewInt(1e16),
+		ToAddress:         to,
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	})
+	_, err := sdkClient.SendRawTx(types.TxTypeWithdraw, string(txInfo))
+	assert.ErrorIs(t, err, &client.APIError{Code: zkbnbtest.CodeInvalidTxField})
+
+	_, err = sdkClient.Withdraw(&types.WithdrawTxReq{ToAddress: to, AssetAmount: big.NewInt(1e17)}, nil)
+	assert.NoError(t, err)
+	sealed := server.SealBlock()
+
+	block, err := sdkClient.GetBlockByHeight(sealed.Height)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), block.PriorityOperations)
+	ops, err := types.DecodePendingOnChainOperations(block.PendingOnChainOperationsPubData)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.OnChainOperation{&types.WithdrawOperation{
+		FromAccountIndex:  index,
+		ToAddress:         to,
+		AssetAmount:       big.NewInt(1e17),
+		GasFeeAssetAmount: zkbnbtest.DefaultGasFee,
+	}}, ops)
+}
@@ -0,0 +1,196 @@
+package zkbnbtest
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	sdkaccounts "github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+)
+
+// SignerServer is a stand-in for a remote L1 signing service such as Clef or Web3Signer, to
+// be used with signer.NewRemoteL1Signer. It answers eth_accounts, eth_sign and
+// eth_signTypedData_v4 JSON-RPC requests with the keys it holds, and rejects requests without
+// the bearer token when one is set. It also holds the EdDSA keys added with AddEdDSAKey, to be
+// used with accounts.NewRemoteKeyManager.
+type SignerServer struct {
+	*httptest.Server
+
+	token string
+
+	mu       sync.Mutex
+	keys     map[common.Address]*ecdsa.PrivateKey
+	l2Keys   map[string]sdkaccounts.KeyManager
+	delay    time.Duration
+	requests int
+}
+
+// NewSignerServer starts a signing service holding the given keys. An empty token disables
+// the authentication.
+func NewSignerServer(token string, keys ...*ecdsa.PrivateKey) *SignerServer {
+	s := &SignerServer{
+		token:  token,
+		keys:   make(map[common.Address]*ecdsa.PrivateKey),
+		l2Keys: make(map[string]sdkaccounts.KeyManager),
+	}
+	for _, key := range keys {
+		s.keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// AddEdDSAKey adds an EdDSA key, to be signed with under the key id.
+func (s *SignerServer) AddEdDSAKey(keyId string, keyManager sdkaccounts.KeyManager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l2Keys[keyId] = keyManager
+}
+
+// SetDelay delays every response, e.g. to test timeouts.
+func (s *SignerServer) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+// Requests returns the number of authenticated requests served.
+func (s *SignerServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+type signerRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type signerError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *SignerServer) serve(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	request := &signerRequest{}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(request) != nil {
+		http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests++
+	delay := s.delay
+	s.mu.Unlock()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	result, rpcErr := s.handle(request)
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (s *SignerServer) handle(request *signerRequest) (interface{}, *signerError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch request.Method {
+	case "eth_accounts":
+		addresses := make([]string, 0, len(s.keys))
+		for address := range s.keys {
+			addresses = append(addresses, address.Hex())
+		}
+		return addresses, nil
+	case signer.RemoteSignMethod:
+		var address, data string
+		if len(request.Params) != 2 || json.Unmarshal(request.Params[0], &address) != nil || json.Unmarshal(request.Params[1], &data) != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid params"}
+		}
+		body, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid data: " + err.Error()}
+		}
+		return s.sign(address, accounts.TextHash(body))
+	case signer.RemoteSignTypedDataMethod:
+		var address string
+		var typedData apitypes.TypedData
+		if len(request.Params) != 2 || json.Unmarshal(request.Params[0], &address) != nil || json.Unmarshal(request.Params[1], &typedData) != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid params"}
+		}
+		hash, _, err := apitypes.TypedDataAndHash(typedData)
+		if err != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid typed data: " + err.Error()}
+		}
+		return s.sign(address, hash)
+	case sdkaccounts.RemoteEdDSAPubKeyMethod:
+		var keyId string
+		if len(request.Params) != 1 || json.Unmarshal(request.Params[0], &keyId) != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid params"}
+		}
+		keyManager, ok := s.l2Keys[keyId]
+		if !ok {
+			return nil, &signerError{Code: -32000, Message: "unknown key " + keyId}
+		}
+		return hexutil.Encode(keyManager.PubKey().Bytes()), nil
+	case sdkaccounts.RemoteEdDSASignMethod:
+		var keyId, data string
+		if len(request.Params) != 2 || json.Unmarshal(request.Params[0], &keyId) != nil || json.Unmarshal(request.Params[1], &data) != nil {
+			return nil, &signerError{Code: -32602, Message: "invalid params"}
+		}
+		keyManager, ok := s.l2Keys[keyId]
+		if !ok {
+			return nil, &signerError{Code: -32000, Message: "unknown key " + keyId}
+		}
+		message, err := hexutil.Decode(data)
+		if err != nil || len(message) == 0 || len(message)%32 != 0 {
+			return nil, &signerError{Code: -32602, Message: "invalid data, expected field elements: " + data}
+		}
+		signature, err := keyManager.Sign(message, mimc.NewMiMC())
+		if err != nil {
+			return nil, &signerError{Code: -32000, Message: err.Error()}
+		}
+		return hexutil.Encode(signature), nil
+	default:
+		return nil, &signerError{Code: -32601, Message: "the method " + request.Method + " does not exist"}
+	}
+}
+
+func (s *SignerServer) sign(address string, hash []byte) (interface{}, *signerError) {
+	key, ok := s.keys[common.HexToAddress(address)]
+	if !ok || !common.IsHexAddress(address) {
+		return nil, &signerError{Code: -32000, Message: "unknown account " + strings.ToLower(address)}
+	}
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, &signerError{Code: -32000, Message: err.Error()}
+	}
+	signature[64] += 27
+	return hexutil.Encode(signature), nil
+}
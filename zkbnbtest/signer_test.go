@@ -0,0 +1,251 @@
+package zkbnbtest_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+func TestRemoteL1Signer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	signerServer := zkbnbtest.NewSignerServer("secret", key)
+	defer signerServer.Close()
+
+	remote, err := signer.NewRemoteL1Signer(signerServer.URL, l1Address, signer.WithRemoteSignerBearerToken("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, l1Address, remote.GetAddress())
+	assert.Empty(t, remote.GetPublicKey())
+	local, err := signer.NewL1Singer(common.Bytes2Hex(crypto.FromECDSA(key)))
+	assert.NoError(t, err)
+	signature, err := remote.Sign("Transfer 1 BNB")
+	assert.NoError(t, err)
+	expected, _ := local.Sign("Transfer 1 BNB")
+	assert.Equal(t, expected, signature)
+	assert.Equal(t, local.GetPublicKey(), remote.GetPublicKey())
+
+	// the requests are authenticated
+	unauthorized, err := signer.NewRemoteL1Signer(signerServer.URL, l1Address, signer.WithRemoteSignerBearerToken("wrong"))
+	assert.NoError(t, err)
+	_, err = unauthorized.Sign("Transfer 1 BNB")
+	assert.ErrorContains(t, err, "401")
+	assert.Equal(t, 1, signerServer.Requests())
+
+	// the remote signer only holds its own keys
+	unknown, err := signer.NewRemoteL1Signer(signerServer.URL, "0x000000000000000000000000000000000000dEaD", signer.WithRemoteSignerBearerToken("secret"))
+	assert.NoError(t, err)
+	_, err = unknown.Sign("Transfer 1 BNB")
+	assert.ErrorContains(t, err, "unknown account")
+	_, err = signer.NewRemoteL1Signer(signerServer.URL, "dead")
+	assert.Error(t, err)
+
+	// and slow requests time out
+	signerServer.SetDelay(time.Second)
+	slow, err := signer.NewRemoteL1Signer(signerServer.URL, l1Address, signer.WithRemoteSignerBearerToken("secret"), signer.WithRemoteSignerTimeout(50*time.Millisecond))
+	assert.NoError(t, err)
+	start := time.Now()
+	_, err = slow.Sign("Transfer 1 BNB")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = remote.SignWithContext(ctx, "Transfer 1 BNB")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientWithRemoteL1Signer(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	key, _ := crypto.GenerateKey()
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	index := server.AddAccount(l1Address, "")
+	server.SetBalance(index, 0, big.NewInt(1e18))
+	signerServer := zkbnbtest.NewSignerServer("", key)
+	defer signerServer.Close()
+
+	remote, err := signer.NewRemoteL1Signer(signerServer.URL, l1Address)
+	assert.NoError(t, err)
+	sdkClient, err := client.NewZkBNBClientWithL1Signer(server.URL, remote, chainId)
+	assert.NoError(t, err)
+	// the L2 key is the one derived from the private key
+	local, err := client.NewZkBNBClientWithPrivateKey(server.URL, common.Bytes2Hex(crypto.FromECDSA(key)), chainId)
+	assert.NoError(t, err)
+	assert.Equal(t, local.KeyManager().PubKey().Bytes(), sdkClient.KeyManager().PubKey().Bytes())
+
+	pubKey := sdkClient.KeyManager().PubKeyPoint()
+	_, err = sdkClient.ChangePubKey(&types.ChangePubKeyReq{L1Address: l1Address, PubKeyX: pubKey[0], PubKeyY: pubKey[1]}, nil)
+	assert.NoError(t, err)
+	_, err = sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e17)}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), server.Account(index).Nonce)
+}
+
+func TestTypedDataSigning(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	contract := "0x000000000000000000000000000000000000bEEF"
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	index := server.AddAccount(l1Address, "")
+	server.SetBalance(index, 0, big.NewInt(1e18))
+
+	// servers without typed data support get personal signatures
+	sdkClient, err := client.NewZkBNBClientWithPrivateKeyAndOptions(server.URL, privateKey, chainId, client.WithExperimentalTypedDataSigning(""))
+	assert.NoError(t, err)
+	mode, err := sdkClient.L1SignMode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, signer.L1SignModePersonal, mode)
+
+	server.EnableTypedDataSigning(chainId, contract)
+	signerServer := zkbnbtest.NewSignerServer("", key)
+	defer signerServer.Close()
+	remote, err := signer.NewRemoteL1Signer(signerServer.URL, l1Address)
+	assert.NoError(t, err)
+	sdkClient, err = client.NewZkBNBClientWithL1Signer(server.URL, remote, chainId, client.WithExperimentalTypedDataSigning(""))
+	assert.NoError(t, err)
+	mode, err = sdkClient.L1SignMode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, signer.L1SignModeTypedData, mode)
+
+	pubKey := sdkClient.KeyManager().PubKeyPoint()
+	changePubKey := &types.ChangePubKeyReq{L1Address: l1Address, PubKeyX: pubKey[0], PubKeyY: pubKey[1]}
+	typedData, err := sdkClient.GenerateTypedData(context.Background(), changePubKey, &types.TransactOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ChangePubKey", typedData.PrimaryType)
+	assert.Equal(t, contract, typedData.Domain.VerifyingContract)
+
+	// passed signatures are taken as personal signatures unless the context says otherwise
+	personal, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	ops := &types.TransactOpts{ExpiredAt: time.Now().Add(time.Hour).UnixMilli()}
+	body, err := sdkClient.GenerateSignBody(changePubKey, ops)
+	assert.NoError(t, err)
+	personalSig, err := personal.Sign(body)
+	assert.NoError(t, err)
+	typedCtx := client.WithL1SignMode(context.Background(), signer.L1SignModeTypedData)
+	_, err = sdkClient.ChangePubKeyWithContext(typedCtx, changePubKey, ops, personalSig)
+	assert.ErrorIs(t, err, &client.APIError{Code: zkbnbtest.CodeVerificationFailed})
+	_, err = sdkClient.ChangePubKey(changePubKey, ops, personalSig)
+	assert.NoError(t, err)
+
+	// the signatures of the client follow the negotiated sign mode
+	_, err = sdkClient.ChangePubKey(changePubKey, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Bytes2Hex(sdkClient.KeyManager().PubKey().Bytes()), server.Account(index).Pk)
+
+	// raw txs signed with personal signatures, e.g. through an envelope, can still be sent
+	nonce, err := sdkClient.GetNextNonce(index)
+	assert.NoError(t, err)
+	envelope, err := txutils.NewEnvelope(changePubKey, &types.TransactOpts{
+		FromAccountIndex:  index,
+		Nonce:             nonce,
+		GasAccountIndex:   zkbnbtest.GasAccountIndex,
+		GasFeeAssetAmount: zkbnbtest.DefaultGasFee,
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, envelope.SignL2(sdkClient.KeyManager()))
+	assert.NoError(t, envelope.SignL1(personal))
+	txType, txInfo, err := envelope.Finalize(common.Bytes2Hex(sdkClient.KeyManager().PubKey().Bytes()), personal.GetAddress())
+	assert.NoError(t, err)
+	_, err = sdkClient.SendRawTx(txType, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, nonce+1, server.Account(index).Nonce)
+
+	// envelopes signed with typed data carry the sign mode the tx is sent with
+	envelope, err = txutils.NewEnvelope(changePubKey, &types.TransactOpts{
+		FromAccountIndex:  index,
+		Nonce:             nonce + 1,
+		GasAccountIndex:   zkbnbtest.GasAccountIndex,
+		GasFeeAssetAmount: zkbnbtest.DefaultGasFee,
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, envelope.SignL2(sdkClient.KeyManager()))
+	assert.NoError(t, envelope.SignL1TypedData(remote, txutils.TypedDataDomain(chainId, contract)))
+	data, err := envelope.MarshalBinary()
+	assert.NoError(t, err)
+	envelope, err = txutils.DecodeEnvelope(data)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.L1SignModeTypedData, envelope.L1SignMode)
+	txType, txInfo, err = envelope.Finalize(common.Bytes2Hex(sdkClient.KeyManager().PubKey().Bytes()), l1Address)
+	assert.NoError(t, err)
+	_, err = sdkClient.SendRawTxWithContext(client.WithL1SignMode(context.Background(), envelope.L1SignMode), txType, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, nonce+2, server.Account(index).Nonce)
+
+	// clients sticking to personal signatures keep working
+	legacy, err := client.NewZkBNBClientWithPrivateKey(server.URL, privateKey, chainId)
+	assert.NoError(t, err)
+	_, err = legacy.ChangePubKey(changePubKey, nil)
+	assert.NoError(t, err)
+	_, err = legacy.GenerateTypedData(context.Background(), changePubKey, nil)
+	assert.Error(t, err)
+}
+
+func TestRemoteKeyManager(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	key, _ := crypto.GenerateKey()
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	l1Address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	index := server.AddAccount(l1Address, "")
+	server.SetBalance(index, 0, big.NewInt(1e18))
+
+	seed, err := accounts.GenerateSeed(privateKey, chainId)
+	assert.NoError(t, err)
+	local, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	signerServer := zkbnbtest.NewSignerServer("secret")
+	defer signerServer.Close()
+	signerServer.AddEdDSAKey("l2", local)
+
+	remote, err := accounts.NewRemoteKeyManager(signerServer.URL, "l2", signer.WithRemoteSignerBearerToken("secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, local.PubKey().Bytes(), remote.PubKey().Bytes())
+	assert.Equal(t, local.PubKeyPoint(), remote.PubKeyPoint())
+	_, err = accounts.NewRemoteKeyManager(signerServer.URL, "unknown", signer.WithRemoteSignerBearerToken("secret"))
+	assert.ErrorContains(t, err, "unknown key")
+	_, err = accounts.NewRemoteKeyManager(signerServer.URL, "l2")
+	assert.ErrorContains(t, err, "401")
+
+	l1Signer, err := signer.NewL1Singer(privateKey)
+	assert.NoError(t, err)
+	sdkClient, err := client.NewZkBNBClientWithKeyManager(server.URL, remote, l1Signer, chainId)
+	assert.NoError(t, err)
+	pubKey := sdkClient.KeyManager().PubKeyPoint()
+	_, err = sdkClient.ChangePubKey(&types.ChangePubKeyReq{L1Address: l1Address, PubKeyX: pubKey[0], PubKeyY: pubKey[1]}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Bytes2Hex(local.PubKey().Bytes()), server.Account(index).Pk)
+	_, err = sdkClient.Transfer(&types.TransferTxReq{To: "0x000000000000000000000000000000000000dEaD", AssetAmount: big.NewInt(1e17)}, nil)
+	assert.NoError(t, err)
+
+	// the remote signer hashes with MiMC only
+	_, err = remote.Sign(common.LeftPadBytes([]byte("zkbnb"), 32), sha256.New())
+	assert.ErrorContains(t, err, "only hashes with MiMC")
+	_, err = remote.Sign(common.LeftPadBytes([]byte("zkbnb"), 32), nil)
+	assert.ErrorContains(t, err, "only hashes with MiMC")
+
+	// signatures of another key are rejected
+	otherKey, _ := crypto.GenerateKey()
+	otherSeed, _ := accounts.GenerateSeed(common.Bytes2Hex(crypto.FromECDSA(otherKey)), chainId)
+	other, _ := accounts.NewSeedKeyManager(otherSeed)
+	signerServer.AddEdDSAKey("l2", other)
+	_, err = remote.Sign(common.LeftPadBytes([]byte("zkbnb"), 32), mimc.NewMiMC())
+	assert.ErrorContains(t, err, "invalid signature")
+}
@@ -0,0 +1,80 @@
+package zkbnbtest_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+// subscribe runs the subscription in the background and returns its events as
+// "type status counterparty amount" strings.
+func subscribe(ctx context.Context, subscription *client.AccountSubscription) <-chan string {
+	events := make(chan string, 100)
+	go func() {
+		_ = subscription.Run(ctx, func(ctx context.Context, event *client.AccountEvent) error {
+			events <- fmt.Sprintf("%s %d %s %s", event.Type, event.Status, event.Counterparty, event.Amount)
+			return nil
+		})
+	}()
+	return events
+}
+
+func expectEvents(t *testing.T, events <-chan string, expected ...string) {
+	for _, e := range expected {
+		select {
+		case event := <-events:
+			assert.Equal(t, e, event)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("missing event %s", e)
+		}
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %s", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAccountSubscription(t *testing.T) {
+	server := zkbnbtest.NewServer()
+	defer server.Close()
+	sdkClient, index, aliceAddress := newTestClient(t, server)
+	key, _ := crypto.GenerateKey()
+	bobAddress := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alice := subscribe(ctx, client.NewAccountSubscriptionByIndex(sdkClient, index, client.SubscribeWithPollInterval(10*time.Millisecond)))
+	bob := subscribe(ctx, client.NewAccountSubscriptionByL1Address(sdkClient, bobAddress, client.SubscribeWithPollInterval(10*time.Millisecond)))
+	// the txs in flight are emitted on start
+	expectEvents(t, alice, "ChangePubKey 1  <nil>")
+	expectEvents(t, bob)
+
+	// the pending deposit is seen before the account exists for the api
+	server.Deposit(bobAddress, 0, big.NewInt(1e18))
+	expectEvents(t, bob, "Deposit 0  1000000000000000000")
+
+	_, err := sdkClient.Transfer(&types.TransferTxReq{To: bobAddress, AssetAmount: big.NewInt(1e17)}, nil)
+	assert.NoError(t, err)
+	expectEvents(t, alice, fmt.Sprintf("TransferOut 1 %s 100000000000000000", bobAddress))
+	expectEvents(t, bob, fmt.Sprintf("TransferIn 1 %s 100000000000000000", aliceAddress))
+
+	server.SealBlock()
+	expectEvents(t, alice, "ChangePubKey 2  <nil>", fmt.Sprintf("TransferOut 2 %s 100000000000000000", bobAddress))
+	expectEvents(t, bob, "Deposit 2  1000000000000000000", fmt.Sprintf("TransferIn 2 %s 100000000000000000", aliceAddress))
+	server.VerifyBlocks()
+	expectEvents(t, bob, "Deposit 4  1000000000000000000", fmt.Sprintf("TransferIn 4 %s 100000000000000000", aliceAddress))
+
+	// a new subscription skips the verified txs
+	bob = subscribe(ctx, client.NewAccountSubscriptionByL1Address(sdkClient, bobAddress, client.SubscribeWithPollInterval(10*time.Millisecond)))
+	expectEvents(t, bob)
+}
@@ -0,0 +1,272 @@
+package zkbnbtest
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/signer"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func parseTx(r *http.Request) (txtypes.TxInfo, *apiError) {
+	txType, err := strconv.ParseUint(r.FormValue("tx_type"), 10, 32)
+	if err != nil {
+		return nil, newAPIError(CodeInvalidTxType, "invalid tx type")
+	}
+	tx, err := txutils.ParseTxInfo(uint32(txType), r.FormValue("tx_info"))
+	if err != nil {
+		return nil, newAPIError(CodeInvalidTxType, "%s", err)
+	}
+	return tx, nil
+}
+
+// verifyL1Sig verifies the L1 signature of the tx in the given l1 sign mode, an empty mode
+// being the personal sign mode.
+func (s *Server) verifyL1Sig(tx txtypes.TxInfo, l1Address string, l1SignMode string) *apiError {
+	var err error
+	switch signer.L1SignMode(l1SignMode) {
+	case "", signer.L1SignModePersonal:
+		err = txutils.VerifyL1Sig(tx, l1Address)
+	case signer.L1SignModeTypedData:
+		if s.typedDataDomain == nil {
+			return newAPIError(CodeInvalidTxField, "unsupported l1 sign mode %s", l1SignMode)
+		}
+		err = txutils.VerifyL1TypedDataSig(tx, l1Address, *s.typedDataDomain)
+	default:
+		return newAPIError(CodeInvalidTxField, "unsupported l1 sign mode %s", l1SignMode)
+	}
+	if err != nil {
+		return newAPIError(CodeVerificationFailed, "%s", err)
+	}
+	return nil
+}
+
+// applyTx checks the tx against the state of the fake and applies it. AtomicMatch and
+// CancelOffer only consume the nonce and the gas fee.
+func (s *Server) applyTx(tx txtypes.TxInfo, txInfo string, l1SignMode string) (string, *apiError) {
+	if err := tx.Validate(); err != nil {
+		return "", newAPIError(CodeInvalidTxField, "%s", err)
+	}
+	if tx.GetExpiredAt() < time.Now().UnixMilli() {
+		return "", newAPIError(CodeInvalidTxField, "tx expired")
+	}
+	account := s.account(tx.GetAccountIndex())
+	if account == nil {
+		return "", errAccountNotFound
+	}
+	if tx.GetNonce() != account.Nonce {
+		return "", newAPIError(CodeInvalidNonce, "invalid nonce, expected %d", account.Nonce)
+	}
+
+	pubKey := account.Pk
+	if _, ok := tx.(*txtypes.ChangePubKeyInfo); ok {
+		pubKey = tx.GetPubKey()
+		if apiErr := s.verifyL1Sig(tx, account.L1Address, l1SignMode); apiErr != nil {
+			return "", apiErr
+		}
+	}
+	if pubKey == "" {
+		return "", newAPIError(CodeVerificationFailed, "the account has no public key")
+	}
+	if err := txutils.VerifyTxSig(uint32(tx.GetTxType()), txInfo, pubKey); err != nil {
+		return "", newAPIError(CodeVerificationFailed, "%s", err)
+	}
+
+	gasAccountIndex, gasAssetId, gasAmount := tx.GetGas()
+	if gasAccountIndex != GasAccountIndex {
+		return "", newAPIError(CodeInvalidTxField, "invalid gas account index")
+	}
+	debits := map[int64]*big.Int{gasAssetId: new(big.Int).Set(gasAmount)}
+	debit := func(assetId int64, amount *big.Int) {
+		if _, ok := debits[assetId]; !ok {
+			debits[assetId] = new(big.Int)
+		}
+		debits[assetId].Add(debits[assetId], amount)
+	}
+
+	hash, err := txutils.ComputeTxHash(uint32(tx.GetTxType()), txInfo)
+	if err != nil {
+		return "", newAPIError(CodeInvalidTxField, "%s", err)
+	}
+	record := &types.EnrichedTx{Tx: types.Tx{
+		Hash:             hash,
+		Type:             int64(tx.GetTxType()),
+		Info:             txInfo,
+		Status:           types.TxStatusExecuted,
+		AccountIndex:     account.Index,
+		L1Address:        account.L1Address,
+		Nonce:            tx.GetNonce(),
+		ExpiredAt:        tx.GetExpiredAt(),
+		GasFeeAssetId:    gasAssetId,
+		GasFee:           gasAmount.String(),
+		FromAccountIndex: account.Index,
+		FromL1Address:    account.L1Address,
+		ToAccountIndex:   -1,
+		NftIndex:         -1,
+		CollectionId:     -1,
+		AssetId:          -1,
+		CreatedAt:        time.Now().Unix(),
+	}}
+
+	// effects are collected first and only applied once all checks passed
+	var effects []func()
+	switch tx := tx.(type) {
+	case *txtypes.ChangePubKeyInfo:
+		effects = append(effects, func() { account.Pk = pubKey })
+	case *txtypes.TransferTxInfo:
+		debit(tx.AssetId, tx.AssetAmount)
+		record.AssetId, record.Amount, record.ToL1Address = tx.AssetId, tx.AssetAmount.String(), tx.ToL1Address
+		effects = append(effects, func() {
+			to := s.accountOrNew(tx.ToL1Address)
+			to.balance(tx.AssetId).Add(to.balance(tx.AssetId), tx.AssetAmount)
+			record.ToAccountIndex = to.Index
+		})
+	case *txtypes.WithdrawTxInfo:
+		// the withdrawal goes into the pubdata of the block, which packs its gas fee
+		if _, err := types.EncodeOnChainOperation(withdrawOperation(tx)); err != nil {
+			return "", newAPIError(CodeInvalidTxField, "%s", err)
+		}
+		debit(tx.AssetId, tx.AssetAmount)
+		record.AssetId, record.Amount, record.ToL1Address = tx.AssetId, tx.AssetAmount.String(), tx.ToAddress
+	case *txtypes.CreateCollectionTxInfo:
+		effects = append(effects, func() {
+			s.collections[account.Index]++
+			record.CollectionId = s.collections[account.Index]
+		})
+	case *txtypes.MintNftTxInfo:
+		record.ToL1Address, record.CollectionId = tx.ToL1Address, tx.NftCollectionId
+		effects = append(effects, func() {
+			to := s.accountOrNew(tx.ToL1Address)
+			nft := &types.Nft{
+				Index:               s.nextNftIndex,
+				CreatorAccountIndex: account.Index,
+				CreatorL1Address:    account.L1Address,
+				OwnerAccountIndex:   to.Index,
+				OwnerL1Address:      to.L1Address,
+				ContentHash:         tx.NftContentHash,
+				NftContentType:      tx.NftContentType,
+				RoyaltyRate:         tx.RoyaltyRate,
+				CollectionId:        tx.NftCollectionId,
+				IpnsId:              tx.IpnsId,
+				Metadata:            tx.MetaData,
+				MutableAttributes:   tx.MutableAttributes,
+			}
+			s.nextNftIndex++
+			s.nfts[nft.Index] = nft
+			s.nftByTxHash[hash] = nft.Index
+			record.NftIndex, record.ToAccountIndex = nft.Index, to.Index
+		})
+	case *txtypes.TransferNftTxInfo:
+		nft, apiErr := s.ownedNft(account, tx.NftIndex)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		record.NftIndex, record.ToL1Address = tx.NftIndex, tx.ToL1Address
+		effects = append(effects, func() {
+			to := s.accountOrNew(tx.ToL1Address)
+			nft.OwnerAccountIndex, nft.OwnerL1Address = to.Index, to.L1Address
+			record.ToAccountIndex = to.Index
+		})
+	case *txtypes.WithdrawNftTxInfo:
+		if _, apiErr := s.ownedNft(account, tx.NftIndex); apiErr != nil {
+			return "", apiErr
+		}
+		record.NftIndex, record.ToL1Address = tx.NftIndex, tx.ToAddress
+		effects = append(effects, func() { delete(s.nfts, tx.NftIndex) })
+	}
+
+	for assetId, amount := range debits {
+		if account.balance(assetId).Cmp(amount) < 0 {
+			return "", newAPIError(CodeBalanceNotEnough, "balance is not enough")
+		}
+	}
+	for assetId, amount := range debits {
+		account.balance(assetId).Sub(account.balance(assetId), amount)
+	}
+	gasAccount := s.account(GasAccountIndex)
+	gasAccount.balance(gasAssetId).Add(gasAccount.balance(gasAssetId), gasAmount)
+	for _, effect := range effects {
+		effect()
+	}
+	account.Nonce++
+
+	s.txs = append(s.txs, record)
+	s.pending = append(s.pending, record)
+	s.txByHash[hash] = record
+	return hash, nil
+}
+
+// Deposit records the deposit of an asset from L1, as done by the ZkBNB contract, and
+// returns the hash of the deposit tx. The account is created if needed and credited at once,
+// the tx stays pending until a block is sealed.
+func (s *Server) Deposit(l1Address string, assetId int64, amount *big.Int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account := s.accountOrNew(l1Address)
+	account.balance(assetId).Add(account.balance(assetId), amount)
+	txInfo, _ := types.MarshalTxInfo(&types.DepositTxInfo{
+		TxType:       types.TxTypeDeposit,
+		AccountIndex: account.Index,
+		L1Address:    account.L1Address,
+		AssetId:      assetId,
+		AssetAmount:  new(big.Int).Set(amount),
+	})
+	record := &types.EnrichedTx{Tx: types.Tx{
+		Hash:             common.Bytes2Hex(crypto.Keccak256([]byte(txInfo), big.NewInt(int64(len(s.txs))).Bytes())),
+		Type:             types.TxTypeDeposit,
+		Info:             txInfo,
+		Status:           types.TxStatusPending,
+		AccountIndex:     account.Index,
+		L1Address:        account.L1Address,
+		AssetId:          assetId,
+		Amount:           amount.String(),
+		FromAccountIndex: -1,
+		ToAccountIndex:   account.Index,
+		ToL1Address:      account.L1Address,
+		NftIndex:         -1,
+		CollectionId:     -1,
+		CreatedAt:        time.Now().Unix(),
+	}}
+	s.txs = append(s.txs, record)
+	s.pending = append(s.pending, record)
+	s.txByHash[record.Hash] = record
+	return record.Hash
+}
+
+func (s *Server) accountOrNew(l1Address string) *Account {
+	if account, ok := s.accountByAddr[strings.ToLower(l1Address)]; ok {
+		return account
+	}
+	return s.addAccount(l1Address, "")
+}
+
+func (s *Server) ownedNft(account *Account, nftIndex int64) (*types.Nft, *apiError) {
+	nft, ok := s.nfts[nftIndex]
+	if !ok {
+		return nil, errNftNotFound
+	}
+	if nft.OwnerAccountIndex != account.Index {
+		return nil, newAPIError(CodeInvalidTxField, "not the owner of the nft")
+	}
+	return nft, nil
+}
+
+// withdrawOperation returns the on-chain operation of a withdrawal.
+func withdrawOperation(tx *txtypes.WithdrawTxInfo) *types.WithdrawOperation {
+	return &types.WithdrawOperation{
+		FromAccountIndex:  tx.FromAccountIndex,
+		ToAddress:         tx.ToAddress,
+		AssetId:           tx.AssetId,
+		AssetAmount:       tx.AssetAmount,
+		GasFeeAssetId:     tx.GasFeeAssetId,
+		GasFeeAssetAmount: tx.GasFeeAssetAmount,
+	}
+}